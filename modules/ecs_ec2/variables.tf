@@ -118,7 +118,7 @@ variable "dd_checks_cardinality" {
   type        = string
   default     = null
   validation {
-    condition     = var.dd_checks_cardinality == null || can(contains(["low", "orchestrator", "high"], var.dd_checks_cardinality))
+    condition     = var.dd_checks_cardinality == null || try(contains(["low", "orchestrator", "high"], var.dd_checks_cardinality), false)
     error_message = "The Datadog Agent checks cardinality must be one of 'low', 'orchestrator', 'high', or null."
   }
 }
@@ -144,7 +144,7 @@ variable "dd_dogstatsd" {
     error_message = "The Datadog Dogstatsd configuration must be defined."
   }
   validation {
-    condition     = try(var.dd_dogstatsd.dogstatsd_cardinality == null, false) || can(contains(["low", "orchestrator", "high"], var.dd_dogstatsd.dogstatsd_cardinality))
+    condition     = try(var.dd_dogstatsd.dogstatsd_cardinality == null, false) || try(contains(["low", "orchestrator", "high"], var.dd_dogstatsd.dogstatsd_cardinality), false)
     error_message = "The Datadog Dogstatsd cardinality must be one of 'low', 'orchestrator', 'high', or null."
   }
 }
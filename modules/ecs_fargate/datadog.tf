@@ -453,16 +453,16 @@ locals {
             try(var.dd_log_collection.fluentbit_config.firelens_options.config_file_value != null, false) ? { config-file-value = var.dd_log_collection.fluentbit_config.firelens_options.config_file_value } : {}
           )
         }
-        cpu              = var.dd_log_collection.fluentbit_config.cpu
-        memory_limit_mib = var.dd_log_collection.fluentbit_config.memory_limit_mib
-        user             = "0"
-        mountPoints      = var.dd_log_collection.fluentbit_config.mountPoints
-        environment      = local.dd_log_environment
-        dockerLabels     = var.dd_docker_labels
-        portMappings     = []
-        systemControls   = []
-        volumesFrom      = []
-        dependsOn        = var.dd_log_collection.fluentbit_config.dependsOn
+        cpu            = var.dd_log_collection.fluentbit_config.cpu
+        memory         = var.dd_log_collection.fluentbit_config.memory_limit_mib
+        user           = "0"
+        mountPoints    = var.dd_log_collection.fluentbit_config.mountPoints
+        environment    = local.dd_log_environment
+        dockerLabels   = var.dd_docker_labels
+        portMappings   = []
+        systemControls = []
+        volumesFrom    = []
+        dependsOn      = var.dd_log_collection.fluentbit_config.dependsOn
       },
       var.dd_log_collection.fluentbit_config.log_router_health_check.command == null ? {} : {
         healthCheck = {
@@ -479,19 +479,19 @@ locals {
   # Datadog CWS tracer definition
   dd_cws_container = local.is_cws_supported ? [
     {
-      name             = "cws-instrumentation-init"
-      image            = "datadog/cws-instrumentation:latest"
-      cpu              = var.dd_cws.cpu
-      memory_limit_mib = var.dd_cws.memory_limit_mib
-      user             = "0"
-      essential        = false
-      entryPoint       = []
-      command          = ["/cws-instrumentation", "setup", "--cws-volume-mount", "/cws-instrumentation-volume"]
-      mountPoints      = local.cws_mount
-      dockerLabels     = var.dd_docker_labels
-      portMappings     = []
-      systemControls   = []
-      volumesFrom      = []
+      name           = "cws-instrumentation-init"
+      image          = "datadog/cws-instrumentation:latest"
+      cpu            = var.dd_cws.cpu
+      memory         = var.dd_cws.memory_limit_mib
+      user           = "0"
+      essential      = false
+      entryPoint     = []
+      command        = ["/cws-instrumentation", "setup", "--cws-volume-mount", "/cws-instrumentation-volume"]
+      mountPoints    = local.cws_mount
+      dockerLabels   = var.dd_docker_labels
+      portMappings   = []
+      systemControls = []
+      volumesFrom    = []
     }
   ] : []
 }
@@ -17,6 +17,7 @@ module "agent_only" {
   }
 }
 
-output "agent_only_task_arn" {
-  value = module.agent_only.arn
+output "agent_only" {
+  value     = module.agent_only
+  sensitive = true
 }
@@ -26,6 +26,8 @@ module "all_features" {
   dd_log_collection = {
     enabled               = true
     container_collect_all = true
+    container_include     = ["name:app", "image:nginx"]
+    container_exclude     = ["name:datadog-agent"]
   }
 
   dd_orchestrator_explorer = {
@@ -42,6 +44,7 @@ module "all_features" {
   }
 }
 
-output "all_features_task_arn" {
-  value = module.all_features.arn
+output "all_features" {
+  value     = module.all_features
+  sensitive = true
 }
@@ -19,10 +19,7 @@ module "bridge_mode" {
   }
 }
 
-output "bridge_mode_task_arn" {
-  value = module.bridge_mode.arn
-}
-
-output "bridge_mode_network_mode" {
-  value = module.bridge_mode.network_mode
+output "bridge_mode" {
+  value     = module.bridge_mode
+  sensitive = true
 }
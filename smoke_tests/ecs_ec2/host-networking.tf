@@ -19,10 +19,7 @@ module "host_mode" {
   }
 }
 
-output "host_mode_task_arn" {
-  value = module.host_mode.arn
-}
-
-output "host_mode_network_mode" {
-  value = module.host_mode.network_mode
+output "host_mode" {
+  value     = module.host_mode
+  sensitive = true
 }
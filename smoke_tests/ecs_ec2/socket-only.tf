@@ -0,0 +1,36 @@
+# Unless explicitly stated otherwise all files in this repository are licensed
+# under the Apache License Version 2.0.
+# This product includes software developed at Datadog (https://www.datadoghq.com/).
+# Copyright 2025-present Datadog, Inc.
+
+# UDS transport only (TCP disabled)
+module "socket_only" {
+  source = "../../modules/ecs_ec2"
+
+  dd_api_key = var.dd_api_key
+  dd_site    = var.dd_site
+
+  dd_dogstatsd = {
+    enabled        = true
+    socket_enabled = true
+    tcp_enabled    = false
+  }
+
+  dd_apm = {
+    enabled        = true
+    socket_enabled = true
+    tcp_enabled    = false
+  }
+
+  family         = "${var.test_prefix}-socket-only"
+  create_service = false
+
+  tags = {
+    Test = "socket-only"
+  }
+}
+
+output "socket_only" {
+  value     = module.socket_only
+  sensitive = true
+}
@@ -30,6 +30,7 @@ module "tcp_enabled" {
   }
 }
 
-output "tcp_enabled_task_arn" {
-  value = module.tcp_enabled.arn
+output "tcp_enabled" {
+  value     = module.tcp_enabled
+  sensitive = true
 }
@@ -49,3 +49,7 @@ module "dd_task_all_dd_disabled" {
 
   requires_compatibilities = ["FARGATE"]
 }
+
+output "all-dd-disabled" {
+  value = module.dd_task_all_dd_disabled
+}
@@ -44,6 +44,8 @@ module "dd_task_all_dd_inputs" {
   dd_log_collection = {
     enabled = true,
     fluentbit_config = {
+      cpu                              = 64,
+      memory_limit_mib                 = 128,
       is_log_router_dependency_enabled = true,
       log_driver_configuration = {
         service_name = "dd-test"
@@ -54,8 +56,9 @@ module "dd_task_all_dd_inputs" {
   }
 
   dd_cws = {
-    enabled = true,
-    cpu     = 100,
+    enabled          = true,
+    cpu              = 100,
+    memory_limit_mib = 64,
   }
 
   dd_orchestrator_explorer = {
@@ -95,3 +98,7 @@ module "dd_task_all_dd_inputs" {
 
   requires_compatibilities = ["FARGATE"]
 }
+
+output "all-dd-inputs" {
+  value = module.dd_task_all_dd_inputs
+}
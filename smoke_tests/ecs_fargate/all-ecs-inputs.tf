@@ -152,3 +152,7 @@ module "dd_task_all_ecs_inputs" {
   }
   track_latest = false
 }
+
+output "all-ecs-inputs" {
+  value = module.dd_task_all_ecs_inputs
+}
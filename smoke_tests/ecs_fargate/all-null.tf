@@ -76,3 +76,7 @@ module "dd_task_all_null" {
   # requires_compatibilities = null
 
 }
+
+output "all-null" {
+  value = module.dd_task_all_null
+}
@@ -46,3 +46,7 @@ module "dd_task_all_windows" {
     operating_system_family = "WINDOWS_SERVER_2022_CORE"
   }
 }
+
+output "all-windows" {
+  value = module.dd_task_all_windows
+}
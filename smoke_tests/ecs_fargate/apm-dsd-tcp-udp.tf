@@ -46,3 +46,7 @@ module "dd_task_apm_dsd_tcp_udp" {
   volumes                  = []
   requires_compatibilities = ["FARGATE"]
 }
+
+output "apm-dsd-tcp-udp" {
+  value = module.dd_task_apm_dsd_tcp_udp
+}
@@ -3,6 +3,8 @@
 # This product includes software developed at Datadog (https://www.datadoghq.com/).
 # Copyright 2025-present Datadog, Inc.
 
+# Code generated from tests/scenarios/cws-only.yaml by TestRenderScenarios. DO NOT EDIT.
+
 ################################################################################
 # Task Definition: CWS
 ################################################################################
@@ -11,46 +13,40 @@
 module "dd_task_cws_only" {
   source = "../../modules/ecs_fargate"
 
-  dd_api_key                       = var.dd_api_key
-  dd_site                          = var.dd_site
-  dd_service                       = var.dd_service
-  dd_tags                          = "team:cont-p, owner:container-monitoring"
-  dd_is_datadog_dependency_enabled = true
-
-  dd_environment = []
-
-  dd_dogstatsd = {
+  container_definitions = jsonencode([
+    {
+      entryPoint = ["/usr/bin/bash", "-c", "cp /usr/bin/bash /tmp/malware; chmod u+s /tmp/malware; apt update;apt install -y curl wget; /tmp/malware -c 'while true; do wget https://google.com; sleep 60; done'"]
+      essential  = true
+      image      = "public.ecr.aws/ubuntu/ubuntu:22.04_stable"
+      name       = "datadog-cws-app"
+    }
+  ])
+  dd_api_key = var.dd_api_key
+  dd_apm = {
     enabled = false
   }
-
-  dd_apm = {
-    enabled = false,
+  dd_cws = {
+    enabled = true
   }
-
-  dd_log_collection = {
-    enabled = false,
+  dd_dogstatsd = {
+    enabled = false
   }
-
-  dd_cws = {
-    enabled = true,
+  dd_environment                   = []
+  dd_is_datadog_dependency_enabled = true
+  dd_log_collection = {
+    enabled = false
   }
-
-  family = "${var.test_prefix}-cws-only"
-  container_definitions = jsonencode([
-    {
-      name      = "datadog-cws-app",
-      image     = "public.ecr.aws/ubuntu/ubuntu:22.04_stable",
-      essential = true,
-      entryPoint = [
-        "/usr/bin/bash",
-        "-c",
-        "cp /usr/bin/bash /tmp/malware; chmod u+s /tmp/malware; apt update;apt install -y curl wget; /tmp/malware -c 'while true; do wget https://google.com; sleep 60; done'"
-      ],
-    }
-  ])
+  dd_service               = var.dd_service
+  dd_site                  = var.dd_site
+  dd_tags                  = "team:cont-p, owner:container-monitoring"
+  family                   = "${var.test_prefix}-cws-only"
+  requires_compatibilities = ["FARGATE"]
   runtime_platform = {
     cpu_architecture        = "ARM64"
     operating_system_family = "LINUX"
   }
-  requires_compatibilities = ["FARGATE"]
+}
+
+output "cws-only" {
+  value = module.dd_task_cws_only
 }
@@ -47,3 +47,7 @@ module "dd_task_logging_only" {
 
   requires_compatibilities = ["FARGATE"]
 }
+
+output "logging-only" {
+  value = module.dd_task_logging_only
+}
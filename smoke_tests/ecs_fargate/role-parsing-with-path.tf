@@ -64,3 +64,7 @@ module "dd_task_role_parsing_with_path" {
 
   requires_compatibilities = ["FARGATE"]
 }
+
+output "role-parsing-with-path" {
+  value = module.dd_task_role_parsing_with_path
+}
@@ -64,3 +64,7 @@ module "dd_task_role_parsing_without_path" {
 
   requires_compatibilities = ["FARGATE"]
 }
+
+output "role-parsing-without-path" {
+  value = module.dd_task_role_parsing_without_path
+}
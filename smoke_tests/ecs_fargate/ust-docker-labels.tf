@@ -48,3 +48,7 @@ module "dd_task_ust_docker_labels" {
 
   requires_compatibilities = ["FARGATE"]
 }
+
+output "ust-docker-labels" {
+  value = module.dd_task_ust_docker_labels
+}
@@ -25,3 +25,15 @@ variable "test_prefix" {
   type        = string
   default     = "terraform-test"
 }
+
+variable "plan_only" {
+  description = "Configure the AWS provider to plan without credentials or any AWS API call"
+  type        = bool
+  default     = false
+}
+
+variable "aws_endpoint_url" {
+  description = "URL of a local stand-in for the ECS, EFS, IAM, Secrets Manager and STS APIs, used instead of AWS when set"
+  type        = string
+  default     = null
+}
@@ -6,11 +6,10 @@
 package test
 
 import (
-	"encoding/json"
 	"log"
 
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
-	"github.com/gruntwork-io/terratest/modules/terraform"
 )
 
 // Test for the "all-dd-inputs" task definition
@@ -18,34 +17,12 @@ func (s *ECSFargateSuite) TestAllDDInputs() {
 	log.Println("TestAllDDInputs: Running test...")
 
 	// Retrieve the task output for the "all-dd-inputs" module
-	var containers []types.ContainerDefinition
-	task := terraform.OutputMap(s.T(), s.terraformOptions, "all-dd-inputs")
+	task := s.taskOutput("all-dd-inputs")
 
-	s.Equal(s.testPrefix+"-all-dd-inputs", task["family"], "Unexpected task family name")
+	s.Equal(s.testPrefix+"-all-dd-inputs", task.Family, "Unexpected task family name")
 
-	err := json.Unmarshal([]byte(task["container_definitions"]), &containers)
-	s.NoError(err, "Failed to parse container definitions")
-	s.Equal(7, len(containers), "Expected 6 containers in the task definition")
-
-	initContainer, found := GetContainer(containers, "init-volume")
-	s.True(found, "Container init-volume not found in definitions")
-	s.True(*initContainer.ReadonlyRootFilesystem, "init-volume should have a read-only root filesystem")
-	AssertMountPoint(s.T(), initContainer, MountInitVolume)
-
-	// Test Agent Container
-	agentContainer, found := GetContainer(containers, "datadog-agent")
-	s.True(found, "Container datadog-agent not found in definitions")
-	s.Equal("public.ecr.aws/datadog/agent:latest", *agentContainer.Image, "Unexpected image for datadog-agent")
-	s.True(*agentContainer.Essential, "datadog-agent should be essential")
-	s.Equal(types.LogDriverAwsfirelens, (*agentContainer.LogConfiguration).LogDriver, "Unexpected log driver for datadog-agent")
-
-	AssertPortMapping(s.T(), agentContainer, PortUDP)
-	AssertPortMapping(s.T(), agentContainer, PortTCP)
-	AssertMountPoint(s.T(), agentContainer, MountDdSocket)
-	AssertMountPoint(s.T(), agentContainer, MountAgentConfig)
-	AssertMountPoint(s.T(), agentContainer, MountAgentTmp)
-	AssertMountPoint(s.T(), agentContainer, MountAgentRun)
-	AssertContainerDependency(s.T(), agentContainer, DependencyLogRouter)
+	containers := task.ContainerDefinitions
+	s.Equal(7, len(containers), "Expected 7 containers in the task definition")
 
 	expectedAgentEnvvars := map[string]string{
 		"DD_DOGSTATSD_ORIGIN_DETECTION_CLIENT":         "true",
@@ -64,8 +41,6 @@ func (s *ECSFargateSuite) TestAllDDInputs() {
 		"DD_ORCHESTRATOR_EXPLORER_ORCHESTRATOR_DD_URL": "https://test-orchestrator-explorer.datadoghq.com",
 		// "DD_INSTALL_INFO_INSTALLER_VERSION":        "0.0.0",
 	}
-	AssertEnvVars(s.T(), agentContainer, expectedAgentEnvvars)
-
 	expectedLogOptions := map[string]string{
 		"apikey":      "test-api-key",
 		"provider":    "ecs",
@@ -76,54 +51,6 @@ func (s *ECSFargateSuite) TestAllDDInputs() {
 		"Name":        "datadog",
 		"retry_limit": "2",
 	}
-	for key, expectedValue := range expectedLogOptions {
-		value, exists := agentContainer.LogConfiguration.Options[key]
-		s.True(exists, "Log option %s not found in datadog-agent", key)
-		s.Equal(expectedValue, value, "Log option %s value does not match expected in datadog-agent", key)
-	}
-
-	// Test Log Router Container
-	logRouterContainer, found := GetContainer(containers, "datadog-log-router")
-	s.True(found, "Container datadog-log-router not found in definitions")
-	s.Equal("public.ecr.aws/aws-observability/aws-for-fluent-bit:stable", *logRouterContainer.Image)
-	s.False(*logRouterContainer.Essential, "datadog-log-router should not be essential")
-	s.True(*logRouterContainer.ReadonlyRootFilesystem, "datadog-log-router should have a read-only root filesystem")
-	s.Equal("0", *logRouterContainer.User, "Unexpected user for datadog-log-router")
-	s.Equal(types.FirelensConfigurationTypeFluentbit, logRouterContainer.FirelensConfiguration.Type, "Unexpected firelens type")
-	s.Equal("true", logRouterContainer.FirelensConfiguration.Options["enable-ecs-log-metadata"], "Unexpected firelens option value")
-
-	// Test CWS init container
-	cwsInitContainer, found := GetContainer(containers, "cws-instrumentation-init")
-	s.True(found, "Container cws-instrumentation-init not found in definitions")
-	s.Equal("datadog/cws-instrumentation:latest", *cwsInitContainer.Image)
-	s.False(*cwsInitContainer.Essential, "cws-instrumentation-init should not be essential")
-	s.Equal("0", *cwsInitContainer.User, "Unexpected user for cws-instrumentation-init")
-	s.Equal([]string{"/cws-instrumentation", "setup", "--cws-volume-mount", "/cws-instrumentation-volume"}, cwsInitContainer.Command, "Unexpected command for cws-instrumentation-init")
-	AssertMountPoint(s.T(), cwsInitContainer, MountCWS)
-
-	// Test the datadog-cws-app container
-	cwsAppContainer, found := GetContainer(containers, "datadog-cws-app")
-	s.True(found, "Container datadog-cws-app not found in definitions")
-	AssertMountPoint(s.T(), cwsAppContainer, MountCWS)
-	AssertContainerDependency(s.T(), cwsAppContainer, DependencyCWS)
-	AssertContainerDependency(s.T(), cwsAppContainer, DependencyAgent)
-	s.NotNil(cwsAppContainer.LinuxParameters, "LinuxParameters should not be nil for datadog-cws-app")
-	s.Contains(cwsAppContainer.LinuxParameters.Capabilities.Add, "SYS_PTRACE",
-		"SYS_PTRACE capability should be added for datadog-cws-app")
-	expectedEntryPoint := []string{
-		"/cws-instrumentation-volume/cws-instrumentation",
-		"trace",
-		"--",
-		"/usr/bin/bash",
-		"-c",
-		"cp /usr/bin/bash /tmp/malware; chmod u+s /tmp/malware; apt update;apt install -y curl wget; /tmp/malware -c 'while true; do wget https://google.com; sleep 60; done'",
-	}
-	s.Equal(expectedEntryPoint, cwsAppContainer.EntryPoint, "CWS app entrypoint should be prefixed with the CWS tracer")
-
-	// Test datadog-apm-app container
-	apmAppContainer, found := GetContainer(containers, "datadog-apm-app")
-	s.True(found, "Container datadog-apm-app not found in definitions")
-	s.Equal("ghcr.io/datadog/apps-tracegen:main", *apmAppContainer.Image)
 	expectedApmDsdEnvVars := map[string]string{
 		"DD_SERVICE":           "test-service",
 		"DD_TRACE_AGENT_URL":   "unix:///var/run/datadog/apm.socket",
@@ -132,14 +59,63 @@ func (s *ECSFargateSuite) TestAllDDInputs() {
 		"DD_TRACE_INFERRED_PROXY_SERVICES_ENABLED": "true",
 		"DD_DATA_STREAMS_ENABLED":                  "true",
 	}
-	AssertEnvVars(s.T(), apmAppContainer, expectedApmDsdEnvVars)
-	AssertMountPoint(s.T(), apmAppContainer, MountDdSocket)
-	s.Nil(apmAppContainer.LinuxParameters, "LinuxParameters should be nil for datadog-apm-app")
 
-	// Test datadog-dogstatsd-app container
-	dogstatsdAppContainer, found := GetContainer(containers, "datadog-dogstatsd-app")
-	s.True(found, "Container datadog-dogstatsd-app not found in definitions")
-	s.Equal("ghcr.io/datadog/apps-dogstatsd:main", *dogstatsdAppContainer.Image)
-	AssertEnvVars(s.T(), dogstatsdAppContainer, expectedApmDsdEnvVars)
-	s.Nil(dogstatsdAppContainer.LinuxParameters, "LinuxParameters should be nil for datadog-dogstatsd-app")
+	ecsassert.Expect(s.T(), containers).
+		Container("init-volume").
+		ReadonlyRootFilesystem().
+		HasMount(ecsassert.MountInitVolume).
+		// Test Agent Container
+		Container("datadog-agent").
+		Image("public.ecr.aws/datadog/agent:latest").
+		Essential().
+		LogDriver(types.LogDriverAwsfirelens).
+		HasPort(ecsassert.PortUDP, ecsassert.PortTCP).
+		HasMount(ecsassert.MountDdSocket, ecsassert.MountAgentConfig, ecsassert.MountAgentTmp, ecsassert.MountAgentRun).
+		DependsOn(ecsassert.DependencyLogRouter).
+		HasEnv(expectedAgentEnvvars).
+		HasLogOptions(expectedLogOptions).
+		// Test Log Router Container
+		Container("datadog-log-router").
+		Image("public.ecr.aws/aws-observability/aws-for-fluent-bit:stable").
+		NotEssential().
+		ReadonlyRootFilesystem().
+		User("0").
+		Cpu(64).
+		Memory(128).
+		Firelens(types.FirelensConfigurationTypeFluentbit).
+		HasFirelensOption("enable-ecs-log-metadata", "true").
+		// Test CWS init container
+		Container("cws-instrumentation-init").
+		Image("datadog/cws-instrumentation:latest").
+		NotEssential().
+		User("0").
+		Cpu(100).
+		Memory(64).
+		Command("/cws-instrumentation", "setup", "--cws-volume-mount", "/cws-instrumentation-volume").
+		HasMount(ecsassert.MountCWS).
+		// Test the datadog-cws-app container, its entrypoint is prefixed with the CWS tracer
+		Container("datadog-cws-app").
+		HasMount(ecsassert.MountCWS).
+		DependsOn(ecsassert.DependencyCWS, ecsassert.DependencyAgent).
+		AddsCapabilities("SYS_PTRACE").
+		EntryPoint(
+			"/cws-instrumentation-volume/cws-instrumentation",
+			"trace",
+			"--",
+			"/usr/bin/bash",
+			"-c",
+			"cp /usr/bin/bash /tmp/malware; chmod u+s /tmp/malware; apt update;apt install -y curl wget; /tmp/malware -c 'while true; do wget https://google.com; sleep 60; done'",
+		).
+		// Test datadog-apm-app container
+		Container("datadog-apm-app").
+		Image("ghcr.io/datadog/apps-tracegen:main").
+		HasEnv(expectedApmDsdEnvVars).
+		HasMount(ecsassert.MountDdSocket).
+		NoLinuxParameters().
+		// Test datadog-dogstatsd-app container
+		Container("datadog-dogstatsd-app").
+		Image("ghcr.io/datadog/apps-dogstatsd:main").
+		HasEnv(expectedApmDsdEnvVars).
+		NoLinuxParameters().
+		Check()
 }
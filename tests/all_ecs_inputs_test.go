@@ -7,8 +7,11 @@ package test
 
 import (
 	"log"
+	"strings"
 
-	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 )
 
 // TestAllECSInputs tests that the ECS task definition attributes are properly set
@@ -16,35 +19,55 @@ func (s *ECSFargateSuite) TestAllECSInputs() {
 	log.Println("TestAllECSInputs: Running test...")
 
 	// Retrieve the task output for the "all-ecs-inputs" module
-	task := terraform.OutputMap(s.T(), s.terraformOptions, "all-ecs-inputs")
+	task := s.taskOutput("all-ecs-inputs")
 
-	s.Equal(s.testPrefix+"-all-ecs-inputs", task["family"], "Unexpected task family name")
-	s.Equal("256", task["cpu"], "Unexpected CPU value")
-	s.Equal("512", task["memory"], "Unexpected memory value")
-	s.Equal("awsvpc", task["network_mode"], "Unexpected network mode")
-	s.Equal("task", task["pid_mode"], "Unexpected PID mode")
-	s.Equal("false", task["track_latest"], "Unexpected track_latest value")
+	s.Equal(s.testPrefix+"-all-ecs-inputs", task.Family, "Unexpected task family name")
+	s.Equal("256", task.Cpu, "Unexpected CPU value")
+	s.Equal("512", task.Memory, "Unexpected memory value")
+	s.Equal(types.NetworkModeAwsvpc, task.NetworkMode, "Unexpected network mode")
+	s.Equal(types.PidModeTask, task.PidMode, "Unexpected PID mode")
+	s.False(task.TrackLatest, "Unexpected track_latest value")
 
-	s.Contains(task["ephemeral_storage"], "size_in_gib:40", "Unexpected ephemeral storage size")
+	s.Require().NotNil(task.EphemeralStorage, "Ephemeral storage should be defined")
+	s.Equal(int32(40), task.EphemeralStorage.SizeInGiB, "Unexpected ephemeral storage size")
 
-	s.Contains(task["runtime_platform"], "cpu_architecture:X86_64", "Unexpected CPU architecture")
-	s.Contains(task["runtime_platform"], "operating_system_family:LINUX", "Unexpected OS family")
+	s.Require().NotNil(task.RuntimePlatform, "Runtime platform should be defined")
+	s.Equal(types.CPUArchitectureX8664, task.RuntimePlatform.CpuArchitecture, "Unexpected CPU architecture")
+	s.Equal(types.OSFamilyLinux, task.RuntimePlatform.OperatingSystemFamily, "Unexpected OS family")
 
-	s.Contains(task["proxy_configuration"], "type:APPMESH", "Unexpected proxy configuration type")
-	s.Contains(task["proxy_configuration"], "container_name:datadog-dummy-app", "Unexpected proxy container name")
-	s.Contains(task["proxy_configuration"], "ProxyIngressPort:15000", "Unexpected proxy ingress port")
-	s.Contains(task["proxy_configuration"], "AppPorts:8080", "Unexpected app ports")
-	s.Contains(task["proxy_configuration"], "EgressIgnoredIPs:", "Unexpected egress ignored IPs")
-	s.Contains(task["proxy_configuration"], "IgnoredUID:1337", "Unexpected ignored UID")
-	s.Contains(task["proxy_configuration"], "ProxyEgressPort:15001", "Unexpected proxy egress port")
+	s.Require().NotNil(task.ProxyConfiguration, "Proxy configuration should be defined")
+	s.Equal(types.ProxyConfigurationTypeAppmesh, task.ProxyConfiguration.Type, "Unexpected proxy configuration type")
+	s.Equal("datadog-dummy-app", *task.ProxyConfiguration.ContainerName, "Unexpected proxy container name")
+	s.Equal([]types.KeyValuePair{
+		{Name: aws.String("AppPorts"), Value: aws.String("8080")},
+		{Name: aws.String("EgressIgnoredIPs"), Value: aws.String("169.254.170.2,169.254.169.254")},
+		{Name: aws.String("IgnoredUID"), Value: aws.String("1337")},
+		{Name: aws.String("ProxyEgressPort"), Value: aws.String("15001")},
+		{Name: aws.String("ProxyIngressPort"), Value: aws.String("15000")},
+	}, task.ProxyConfiguration.Properties, "Unexpected proxy configuration properties")
 
-	s.Contains(task["volume"], "efs-storage", "Unexpected volume name")
-	s.Contains(task["volume"], "access_point_id:fsap-", "Unexpected EFS access point ID")
-	s.Contains(task["volume"], "iam:ENABLED", "Unexpected EFS IAM setting")
-	s.Contains(task["volume"], "root_directory:/", "Unexpected EFS root directory")
+	s.Equal(3, len(task.Volumes), "Expected 3 volumes in the task definition")
 
-	s.Contains(task["volume"], "docker-storage", "Unexpected volume name")
-	s.Contains(task["volume"], "dd-sockets", "Unexpected volume name")
+	efsVolume, found := ecsassert.GetVolume(task.Volumes, "efs-storage")
+	s.True(found, "Volume efs-storage not found in task definition")
+	if !s.planOnly {
+		// The EFS configuration references the file system and access point created on apply, so it is unknown at plan
+		s.Require().NotNil(efsVolume.EfsVolumeConfiguration, "efs-storage should have an EFS volume configuration")
+		s.Equal("/", *efsVolume.EfsVolumeConfiguration.RootDirectory, "Unexpected EFS root directory")
+		s.Equal(types.EFSTransitEncryptionEnabled, efsVolume.EfsVolumeConfiguration.TransitEncryption, "Unexpected EFS transit encryption")
+		s.Equal(int32(2999), *efsVolume.EfsVolumeConfiguration.TransitEncryptionPort, "Unexpected EFS transit encryption port")
+		s.Require().NotNil(efsVolume.EfsVolumeConfiguration.AuthorizationConfig, "EFS authorization config should be defined")
+		s.Equal(types.EFSAuthorizationConfigIAMEnabled, efsVolume.EfsVolumeConfiguration.AuthorizationConfig.Iam, "Unexpected EFS IAM setting")
+		s.True(strings.HasPrefix(*efsVolume.EfsVolumeConfiguration.FileSystemId, "fs-"), "Unexpected EFS file system ID")
+		s.True(strings.HasPrefix(*efsVolume.EfsVolumeConfiguration.AuthorizationConfig.AccessPointId, "fsap-"), "Unexpected EFS access point ID")
+	}
 
-	s.Contains(task["requires_compatibilities"], "FARGATE", "Unexpected compatibility setting")
+	dockerVolume, found := ecsassert.GetVolume(task.Volumes, "docker-storage")
+	s.True(found, "Volume docker-storage not found in task definition")
+	s.Nil(dockerVolume.DockerVolumeConfiguration, "docker-storage should not have a docker volume configuration on Fargate")
+
+	_, found = ecsassert.GetVolume(task.Volumes, "dd-sockets")
+	s.True(found, "Volume dd-sockets not found in task definition")
+
+	s.Equal([]types.Compatibility{types.CompatibilityFargate}, task.RequiresCompatibilities, "Unexpected compatibility setting")
 }
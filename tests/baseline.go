@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Rules checked by BaselineViolations on top of those of ValidateTaskDefinition
+const (
+	RuleAgentContainer = "agent-container"
+	RuleInstallInfo    = "install-info"
+	RuleModuleTag      = "module-tag"
+)
+
+// moduleTag is the tag set by both modules on the resources they create, holding the module version
+const moduleTag = "dd_ecs_terraform_module"
+
+// TaskOutputKeys returns the outputs the checks on every output run on: those shaped like the output of a module,
+// and those that cannot be read so the checks report why
+func TaskOutputKeys[T any](outputs *OutputCache[T]) []string {
+	return slices.DeleteFunc(outputs.Keys(), func(key string) bool {
+		data, err := outputs.Raw(key)
+		return err == nil && !IsTaskDefinitionOutput(data)
+	})
+}
+
+// IsTaskDefinitionOutput reports whether a JSON output is shaped like the output of the ecs_fargate or ecs_ec2 module
+func IsTaskDefinitionOutput(data []byte) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false
+	}
+	_, hasFamily := fields["family"]
+	_, hasContainers := fields["container_definitions"]
+	return hasFamily && hasContainers
+}
+
+// BaselineViolations returns the violations of the checks run on every task definition output of the smoke tests,
+// whether or not a test reads it: the rules of ValidateTaskDefinition, a datadog-agent container reporting
+// how it was installed, and the module tag.
+func BaselineViolations(task TaskDefinitionOutput) []Violation {
+	violations := ValidateTaskDefinition(task)
+
+	agent, found := ecsassert.GetContainer(task.ContainerDefinitions, "datadog-agent")
+	if !found {
+		violations = append(violations, Violation{RuleAgentContainer, "", "has no datadog-agent container"})
+	} else if value, found := ecsassert.GetEnvVar(agent, "DD_INSTALL_INFO_TOOL"); !found || value == "" {
+		violations = append(violations, Violation{RuleInstallInfo, aws.ToString(agent.Name), "does not set DD_INSTALL_INFO_TOOL"})
+	}
+
+	if _, found := task.Tags[moduleTag]; !found {
+		violations = append(violations, Violation{RuleModuleTag, "", "is not tagged " + moduleTag})
+	}
+	return violations
+}
+
+// AssertBaseline fails the test for every baseline check the task definition does not pass
+func AssertBaseline(t *testing.T, task TaskDefinitionOutput) {
+	for _, violation := range BaselineViolations(task) {
+		t.Errorf("Task definition %s violates %s", task.Family, violation)
+	}
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsTaskDefinitionOutput tests that only module outputs are detected as task definitions
+func TestIsTaskDefinitionOutput(t *testing.T) {
+	assert.True(t, IsTaskDefinitionOutput([]byte(`{"family": "terraform-test-cws-only", "container_definitions": "[]", "cpu": "256"}`)))
+	assert.False(t, IsTaskDefinitionOutput([]byte(`{"family": "terraform-test-cws-only"}`)))
+	assert.False(t, IsTaskDefinitionOutput([]byte(`"arn:aws:ecs:us-east-1:123456789012:task-definition/terraform-test-cws-only:1"`)))
+	assert.False(t, IsTaskDefinitionOutput([]byte(`[{"family": "a", "container_definitions": "[]"}]`)))
+}
+
+// TestTaskOutputKeys tests that only task definition outputs, and outputs that cannot be read, are checked
+func TestTaskOutputKeys(t *testing.T) {
+	outputs := NewOutputCache(map[string][]byte{
+		"cws-only":        []byte(`{"family": "terraform-test-cws-only", "container_definitions": "[]"}`),
+		"cws-only-arn":    []byte(`"arn:aws:ecs:us-east-1:123456789012:task-definition/terraform-test-cws-only:1"`),
+		"all-dd-disabled": []byte(`{"family": "terraform-test-all-dd-disabled", "container_definitions": "[]"}`),
+	}, map[string]error{"unreadable": errors.New("unknown after apply")}, DecodeFargateTaskOutput)
+	assert.Equal(t, []string{"all-dd-disabled", "cws-only", "unreadable"}, TaskOutputKeys(outputs))
+}
+
+// TestBaselineViolations tests that the baseline checks report a missing agent, install info and module tag
+func TestBaselineViolations(t *testing.T) {
+	agent := types.ContainerDefinition{
+		Name:        aws.String("datadog-agent"),
+		Essential:   aws.Bool(true),
+		Environment: []types.KeyValuePair{{Name: aws.String("DD_INSTALL_INFO_TOOL"), Value: aws.String("terraform")}},
+	}
+	assert.Empty(t, BaselineViolations(TaskDefinitionOutput{
+		ContainerDefinitions: []types.ContainerDefinition{agent},
+		Tags:                 map[string]string{moduleTag: "1.1.1"},
+	}))
+
+	agent.Environment = nil
+	assert.Equal(t, []Violation{
+		{RuleInstallInfo, "datadog-agent", "does not set DD_INSTALL_INFO_TOOL"},
+		{RuleModuleTag, "", "is not tagged dd_ecs_terraform_module"},
+	}, BaselineViolations(TaskDefinitionOutput{ContainerDefinitions: []types.ContainerDefinition{agent}}))
+
+	app := types.ContainerDefinition{
+		Name:      aws.String("app"),
+		DependsOn: []types.ContainerDependency{{ContainerName: aws.String("datadog-agent"), Condition: types.ContainerConditionHealthy}},
+	}
+	assert.Equal(t, []Violation{
+		{RuleDependsOnTarget, "app", "depends on missing container datadog-agent"},
+		{RuleAgentContainer, "", "has no datadog-agent container"},
+	}, BaselineViolations(TaskDefinitionOutput{
+		ContainerDefinitions: []types.ContainerDefinition{app},
+		Tags:                 map[string]string{moduleTag: "1.1.1"},
+	}))
+}
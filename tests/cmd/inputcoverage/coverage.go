@@ -0,0 +1,245 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DataDog/terraform-ecs-datadog/tests/internal/moduleschema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// PathCoverage is a variable, or an attribute nested in its type, and the smoke tests setting it
+type PathCoverage struct {
+	// Path is the variable name followed by the nested attribute names, eg. dd_apm.enabled or
+	// dd_log_collection.fluentbit_config.environment[*].name for the attributes of list elements
+	Path      string
+	Scenarios []string
+}
+
+// Covered reports whether a smoke test sets the path
+func (p PathCoverage) Covered() bool {
+	return len(p.Scenarios) > 0
+}
+
+// ModuleCoverage is the coverage of the variables of a module
+type ModuleCoverage struct {
+	Name  string
+	Paths []PathCoverage
+
+	types map[string]cty.Type
+	index map[string]int
+}
+
+// Report is the coverage of the variables of every module by the smoke tests
+type Report struct {
+	Modules []*ModuleCoverage
+}
+
+// Analyze parses the variables of every module under modulesDir, then the module blocks of the
+// smoke tests under smokeDir, and returns which variable paths they set
+func Analyze(modulesDir string, smokeDir string) (*Report, error) {
+	entries, err := os.ReadDir(modulesDir)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		module, err := LoadModule(filepath.Join(modulesDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		report.Modules = append(report.Modules, module)
+	}
+
+	err = filepath.WalkDir(smokeDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || filepath.Ext(path) != ".tf" {
+			return err
+		}
+		return report.addSmokeTest(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// LoadModule returns the paths of the variables declared in the .tf files of a module, none covered
+func LoadModule(dir string) (*ModuleCoverage, error) {
+	variables, err := moduleschema.Variables(dir)
+	if err != nil {
+		return nil, err
+	}
+	module := &ModuleCoverage{Name: filepath.Base(dir), types: map[string]cty.Type{}, index: map[string]int{}}
+	for _, variable := range variables {
+		module.types[variable.Name] = variable.Type
+		for _, path := range moduleschema.Paths(variable.Name, variable.Type) {
+			module.Paths = append(module.Paths, PathCoverage{Path: path})
+		}
+	}
+	slices.SortFunc(module.Paths, func(a, b PathCoverage) int { return strings.Compare(a.Path, b.Path) })
+	for i, path := range module.Paths {
+		module.index[path.Path] = i
+	}
+	return module, nil
+}
+
+// addSmokeTest marks the variable paths set by the module blocks of a smoke test file
+func (r *Report) addSmokeTest(path string) error {
+	body, err := moduleschema.ParseFile(path)
+	if err != nil {
+		return err
+	}
+	scenario := strings.TrimSuffix(filepath.Base(path), ".tf")
+	for _, block := range body.Blocks {
+		if block.Type != "module" {
+			continue
+		}
+		source, found := block.Body.Attributes["source"]
+		if !found {
+			continue
+		}
+		value, diags := source.Expr.Value(nil)
+		if diags.HasErrors() || value.Type() != cty.String {
+			continue
+		}
+		module := r.Module(filepath.Base(value.AsString()))
+		if module == nil {
+			continue
+		}
+		for name, attribute := range block.Body.Attributes {
+			if typ, found := module.types[name]; found {
+				module.cover(name, typ, attribute.Expr, scenario)
+			}
+		}
+	}
+	return nil
+}
+
+// cover marks the path set to expr by a scenario, then the nested attributes set by object and tuple literals.
+// Attributes set from other expressions, such as variables or function calls, cannot be told apart.
+func (m *ModuleCoverage) cover(path string, typ cty.Type, expr hclsyntax.Expression, scenario string) {
+	if i, found := m.index[path]; found && !slices.Contains(m.Paths[i].Scenarios, scenario) {
+		m.Paths[i].Scenarios = append(m.Paths[i].Scenarios, scenario)
+	}
+
+	switch e := expr.(type) {
+	case *hclsyntax.ParenthesesExpr:
+		m.cover(path, typ, e.Expression, scenario)
+	case *hclsyntax.ConditionalExpr:
+		m.cover(path, typ, e.TrueResult, scenario)
+		m.cover(path, typ, e.FalseResult, scenario)
+	case *hclsyntax.ObjectConsExpr:
+		for _, item := range e.Items {
+			key := objectKey(item.KeyExpr)
+			switch {
+			case key != "" && typ.IsObjectType() && typ.HasAttribute(key):
+				m.cover(path+"."+key, typ.AttributeType(key), item.ValueExpr, scenario)
+			case typ.IsMapType():
+				m.cover(path+moduleschema.ElementPath, typ.ElementType(), item.ValueExpr, scenario)
+			}
+		}
+	case *hclsyntax.TupleConsExpr:
+		if typ.IsListType() || typ.IsSetType() {
+			for _, element := range e.Exprs {
+				m.cover(path+moduleschema.ElementPath, typ.ElementType(), element, scenario)
+			}
+		}
+	}
+}
+
+// objectKey returns the name of an object attribute, either a bare keyword or a literal string
+func objectKey(expr hclsyntax.Expression) string {
+	if keyword := hcl.ExprAsKeyword(expr); keyword != "" {
+		return keyword
+	}
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || value.Type() != cty.String || value.IsNull() {
+		return ""
+	}
+	return value.AsString()
+}
+
+// Module returns the coverage of the module named name, nil if it is unknown
+func (r *Report) Module(name string) *ModuleCoverage {
+	for _, module := range r.Modules {
+		if module.Name == name {
+			return module
+		}
+	}
+	return nil
+}
+
+// Uncovered returns the variables of every module no smoke test sets, as module.variable
+func (r *Report) Uncovered() []string {
+	var uncovered []string
+	for _, module := range r.Modules {
+		for name := range module.types {
+			if !module.Paths[module.index[name]].Covered() {
+				uncovered = append(uncovered, module.Name+"."+name)
+			}
+		}
+	}
+	slices.Sort(uncovered)
+	return uncovered
+}
+
+// Write writes the coverage of every module, with the smoke tests setting each path.
+// Only the uncovered paths are listed when uncoveredOnly is set.
+func (r *Report) Write(w io.Writer, uncoveredOnly bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, module := range r.Modules {
+		covered := 0
+		for _, path := range module.Paths {
+			if path.Covered() {
+				covered++
+			}
+		}
+		fmt.Fprintf(tw, "%s: %d of %d variable paths covered\n", module.Name, covered, len(module.Paths))
+		for _, path := range module.Paths {
+			switch {
+			case !path.Covered():
+				fmt.Fprintf(tw, "  %s\t-\n", path.Path)
+			case !uncoveredOnly:
+				fmt.Fprintf(tw, "  %s\t%s\n", path.Path, strings.Join(path.Scenarios, ", "))
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// Check returns an error listing the uncovered variables missing from the allowlist, and the allowlisted
+// variables that are now covered or no longer exist, which must be removed from it
+func (r *Report) Check(allowed map[string]bool) error {
+	var errs []error
+	uncovered := r.Uncovered()
+	for _, variable := range uncovered {
+		if !allowed[variable] {
+			errs = append(errs, fmt.Errorf("variable %s is not set by any smoke test", variable))
+		}
+	}
+	for variable := range allowed {
+		if !slices.Contains(uncovered, variable) {
+			errs = append(errs, fmt.Errorf("variable %s is allowlisted but is covered or does not exist, remove it from the allowlist", variable))
+		}
+	}
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errors.Join(errs...)
+}
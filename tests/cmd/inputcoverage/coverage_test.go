@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/terraform-ecs-datadog/tests/internal/moduleschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testVariables = `
+variable "dd_api_key" {
+  type = string
+}
+
+variable "dd_apm" {
+  type = object({
+    enabled        = optional(bool, true)
+    socket_enabled = optional(bool, true)
+  })
+}
+
+variable "volumes" {
+  type = list(object({
+    name = string
+    efs_volume_configuration = optional(object({
+      file_system_id = string
+    }))
+  }))
+  default = []
+}
+
+variable "tags" {
+  type    = map(string)
+  default = null
+}
+`
+
+const testSmokeTest = `
+module "dd_task_apm" {
+  source = "../../modules/ecs_fargate"
+
+  dd_api_key = var.dd_api_key
+  dd_apm = var.plan_only ? { enabled = false } : {
+    "socket_enabled" = true
+  }
+  volumes = [
+    {
+      name = "data"
+    },
+  ]
+}
+
+module "other" {
+  source = "../../modules/unknown"
+
+  tags = {}
+}
+`
+
+// writeFile writes a file of a test tree, creating its directory
+func writeFile(t *testing.T, path string, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+// TestAnalyze tests that nested attributes are covered through object and tuple literals, and conditionals
+func TestAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "modules", "ecs_fargate", "variables.tf"), testVariables)
+	writeFile(t, filepath.Join(dir, "smoke_tests", "ecs_fargate", "apm.tf"), testSmokeTest)
+	writeFile(t, filepath.Join(dir, "smoke_tests", "ecs_fargate", "key.tf"), `module "key" {
+  source     = "../../modules/ecs_fargate"
+  dd_api_key = "key"
+}`)
+
+	report, err := Analyze(filepath.Join(dir, "modules"), filepath.Join(dir, "smoke_tests"))
+	require.NoError(t, err)
+	module := report.Module("ecs_fargate")
+	require.NotNil(t, module)
+	assert.Equal(t, []PathCoverage{
+		{Path: "dd_api_key", Scenarios: []string{"apm", "key"}},
+		{Path: "dd_apm", Scenarios: []string{"apm"}},
+		{Path: "dd_apm.enabled", Scenarios: []string{"apm"}},
+		{Path: "dd_apm.socket_enabled", Scenarios: []string{"apm"}},
+		{Path: "tags"},
+		{Path: "volumes", Scenarios: []string{"apm"}},
+		{Path: "volumes[*]", Scenarios: []string{"apm"}},
+		{Path: "volumes[*].efs_volume_configuration"},
+		{Path: "volumes[*].efs_volume_configuration.file_system_id"},
+		{Path: "volumes[*].name", Scenarios: []string{"apm"}},
+	}, module.Paths)
+	assert.Equal(t, []string{"ecs_fargate.tags"}, report.Uncovered())
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out, true))
+	assert.Equal(t, `ecs_fargate: 7 of 10 variable paths covered
+  tags                                                -
+  volumes[*].efs_volume_configuration                 -
+  volumes[*].efs_volume_configuration.file_system_id  -
+
+`, out.String())
+
+	assert.NoError(t, report.Check(map[string]bool{"ecs_fargate.tags": true}))
+	assert.EqualError(t, report.Check(map[string]bool{"ecs_fargate.dd_apm": true}),
+		"variable ecs_fargate.dd_apm is allowlisted but is covered or does not exist, remove it from the allowlist\n"+
+			"variable ecs_fargate.tags is not set by any smoke test")
+}
+
+// TestRepositoryCoverage fails when a variable of the modules is set by no smoke test and not allowlisted
+func TestRepositoryCoverage(t *testing.T) {
+	report, err := Analyze("../../../modules", "../../../smoke_tests")
+	require.NoError(t, err)
+	allowed, err := moduleschema.ReadAllowlist("allowlist.txt")
+	require.NoError(t, err)
+	assert.NoError(t, report.Check(allowed), "Add the variables to a smoke test")
+}
@@ -0,0 +1,49 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Command inputcoverage reports which variables of the modules, and which attributes nested in their
+// object types, are set by the smoke tests. It fails when a variable is set by no smoke test and is
+// not listed in the allowlist.
+//
+// Run it from the repository root:
+//
+//	go run ./tests/cmd/inputcoverage -uncovered
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/DataDog/terraform-ecs-datadog/tests/internal/moduleschema"
+)
+
+func main() {
+	modulesDir := flag.String("modules", "modules", "Directory of the modules")
+	smokeDir := flag.String("smoke-tests", "smoke_tests", "Directory of the smoke tests, read recursively")
+	allowlist := flag.String("allowlist", "tests/cmd/inputcoverage/allowlist.txt", "File listing the module.variable entries allowed to be uncovered")
+	uncoveredOnly := flag.Bool("uncovered", false, "Only list the uncovered variable paths")
+	flag.Parse()
+
+	if err := run(*modulesDir, *smokeDir, *allowlist, *uncoveredOnly); err != nil {
+		fmt.Fprintln(os.Stderr, "inputcoverage:", err)
+		os.Exit(1)
+	}
+}
+
+func run(modulesDir, smokeDir, allowlist string, uncoveredOnly bool) error {
+	report, err := Analyze(modulesDir, smokeDir)
+	if err != nil {
+		return err
+	}
+	if err := report.Write(os.Stdout, uncoveredOnly); err != nil {
+		return err
+	}
+	allowed, err := moduleschema.ReadAllowlist(allowlist)
+	if err != nil {
+		return err
+	}
+	return report.Check(allowed)
+}
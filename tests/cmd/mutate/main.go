@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Command mutate measures how well the offline tests catch bugs in the modules: it makes controlled
+// edits to their HCL, such as flipping && and || or dropping an environment variable of base_env, runs
+// the plan-only tests against each mutant, and reports the mutants the tests did not kill.
+//
+// Run it from the repository root, restricted to a file:
+//
+//	go run ./tests/cmd/mutate -file modules/ecs_fargate/datadog.tf -timeout 1h
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+func main() {
+	root := flag.String("root", ".", "Root of the repository")
+	file := flag.String("file", "", "Only mutate this file, relative to the root (eg. modules/ecs_fargate/datadog.tf)")
+	list := flag.Bool("list", false, "List the mutants without running the tests")
+	run := flag.String("run", "", "Only run the tests matching this go test -run pattern")
+	timeout := flag.Duration("timeout", 2*time.Hour, "Maximum duration of the whole run, the mutants not run by then are reported")
+	mutantTimeout := flag.Duration("mutant-timeout", 30*time.Minute, "Maximum duration of the tests against a single mutant")
+	parallel := flag.Int("parallel", 1, "Number of mutants tested at the same time")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	if err := runMutants(ctx, *root, *file, *list, *run, *mutantTimeout, *parallel); err != nil {
+		fmt.Fprintln(os.Stderr, "mutate:", err)
+		os.Exit(1)
+	}
+}
+
+func runMutants(ctx context.Context, root, file string, list bool, run string, mutantTimeout time.Duration, parallel int) error {
+	mutants, err := FindMutants(root, file)
+	if err != nil {
+		return err
+	}
+	if list {
+		for _, m := range mutants {
+			fmt.Printf("%d\t%s:%d %s\t%s\t%s\n", m.ID, m.File, m.Line, m.Location, m.Operator, m.Description)
+		}
+		return nil
+	}
+
+	// go test stops the tests at its own timeout, 10m by default: leave it past the mutant timeout so that
+	// slow mutants are reported timed out
+	command := []string{"go", "test", "-count=1", "-timeout", (mutantTimeout + time.Minute).String(), "./tests"}
+	if run != "" {
+		command = append(command, "-run", run)
+	}
+	runner := &Runner{
+		Root:    root,
+		Command: command,
+		// The modules are only planned, the tests never create AWS resources. The toggle matrix samples
+		// the same combinations for every mutant, so its results can be reproduced.
+		Env:           []string{"TERRAFORM_PLAN_ONLY=true", "TERRAFORM_MATRIX_SEED=1"},
+		MutantTimeout: mutantTimeout,
+		Parallel:      parallel,
+		Log:           os.Stderr,
+	}
+	fmt.Fprintf(os.Stderr, "Running %s against the unmodified modules...\n", strings.Join(command, " "))
+	if err := runner.Baseline(ctx); err != nil {
+		return err
+	}
+	outcomes, err := runner.Run(ctx, mutants)
+	if reportErr := Report(os.Stdout, outcomes); reportErr != nil {
+		return reportErr
+	}
+	return err
+}
@@ -0,0 +1,229 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Mutation operators
+const (
+	// OpFlipLogic swaps && and ||
+	OpFlipLogic = "flip-logic"
+	// OpFlipEquality swaps == and !=
+	OpFlipEquality = "flip-equality"
+	// OpNegateCondition negates the condition of a ternary
+	OpNegateCondition = "negate-condition"
+	// OpDropElement removes an object from a list literal, eg. an environment variable of base_env
+	OpDropElement = "drop-element"
+)
+
+// skippedBlocks are the blocks holding no module logic: their expressions are not mutated
+var skippedBlocks = map[string]bool{"variable": true, "output": true, "terraform": true}
+
+// operatorSwaps are the binary operators flipped by OpFlipLogic and OpFlipEquality, and their replacement
+var operatorSwaps = map[*hclsyntax.Operation][2]string{
+	hclsyntax.OpLogicalAnd: {"&&", "||"},
+	hclsyntax.OpLogicalOr:  {"||", "&&"},
+	hclsyntax.OpEqual:      {"==", "!="},
+	hclsyntax.OpNotEqual:   {"!=", "=="},
+}
+
+// Mutant is a single controlled edit of a module file: the bytes between start and end are replaced
+type Mutant struct {
+	ID   int
+	File string
+	Line int
+	// Operator is the mutation operator producing the mutant, eg. flip-logic
+	Operator string
+	// Location is the block and attribute holding the mutated expression, eg. locals.is_apm_socket_mount
+	Location    string
+	Description string
+
+	start       int
+	end         int
+	replacement string
+}
+
+// Apply returns the source of the file with the mutation applied
+func (m Mutant) Apply(src []byte) []byte {
+	mutated := make([]byte, 0, len(src)-(m.end-m.start)+len(m.replacement))
+	mutated = append(mutated, src[:m.start]...)
+	mutated = append(mutated, m.replacement...)
+	return append(mutated, src[m.end:]...)
+}
+
+// FindMutants returns the mutants of the .tf files of the modules under root, numbered from 1.
+// When file is set, only that file, relative to root, is mutated.
+func FindMutants(root string, file string) ([]Mutant, error) {
+	var files []string
+	if file != "" {
+		files = []string{filepath.ToSlash(filepath.Clean(file))}
+	} else {
+		matches, err := filepath.Glob(filepath.Join(root, "modules", "*", "*.tf"))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			relative, err := filepath.Rel(root, match)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, filepath.ToSlash(relative))
+		}
+	}
+
+	var mutants []Mutant
+	for _, file := range files {
+		src, err := os.ReadFile(filepath.Join(root, file))
+		if err != nil {
+			return nil, err
+		}
+		fileMutants, err := mutantsOf(file, src)
+		if err != nil {
+			return nil, err
+		}
+		mutants = append(mutants, fileMutants...)
+	}
+	for i := range mutants {
+		mutants[i].ID = i + 1
+	}
+	return mutants, nil
+}
+
+// mutantsOf returns the mutants of a file, in source order
+func mutantsOf(file string, src []byte) ([]Mutant, error) {
+	parsed, diags := hclsyntax.ParseConfig(src, file, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	var mutants []Mutant
+	walkBody(parsed.Body.(*hclsyntax.Body), "", func(location string, expr hclsyntax.Expression) {
+		hclsyntax.VisitAll(expr, func(node hclsyntax.Node) hcl.Diagnostics {
+			for _, mutant := range mutate(src, node) {
+				mutant.File = file
+				mutant.Location = location
+				mutants = append(mutants, mutant)
+			}
+			return nil
+		})
+	})
+	sort.SliceStable(mutants, func(i, j int) bool { return mutants[i].start < mutants[j].start })
+	return mutants, nil
+}
+
+// walkBody calls visit with every attribute expression of body and its nested blocks, named after their block
+func walkBody(body *hclsyntax.Body, location string, visit func(location string, expr hclsyntax.Expression)) {
+	for name, attribute := range body.Attributes {
+		visit(strings.TrimPrefix(location+"."+name, "."), attribute.Expr)
+	}
+	for _, block := range body.Blocks {
+		if location == "" && skippedBlocks[block.Type] {
+			continue
+		}
+		blockLocation := strings.Join(append([]string{block.Type}, block.Labels...), ".")
+		if location != "" {
+			blockLocation = location + "." + blockLocation
+		}
+		walkBody(block.Body, blockLocation, visit)
+	}
+}
+
+// mutate returns the mutants of a single expression
+func mutate(src []byte, node hclsyntax.Node) []Mutant {
+	switch e := node.(type) {
+	case *hclsyntax.BinaryOpExpr:
+		swap, found := operatorSwaps[e.Op]
+		if !found {
+			return nil
+		}
+		between := e.LHS.Range().End.Byte
+		offset := bytes.Index(src[between:e.RHS.Range().Start.Byte], []byte(swap[0]))
+		if offset < 0 {
+			return nil
+		}
+		operator := OpFlipLogic
+		if e.Op == hclsyntax.OpEqual || e.Op == hclsyntax.OpNotEqual {
+			operator = OpFlipEquality
+		}
+		return []Mutant{{
+			Line:        e.SrcRange.Start.Line,
+			Operator:    operator,
+			Description: fmt.Sprintf("%s -> %s in %s", swap[0], swap[1], excerpt(src, e.SrcRange)),
+			start:       between + offset,
+			end:         between + offset + 2,
+			replacement: swap[1],
+		}}
+
+	case *hclsyntax.ConditionalExpr:
+		condition := e.Condition.Range()
+		return []Mutant{{
+			Line:        condition.Start.Line,
+			Operator:    OpNegateCondition,
+			Description: "negate " + excerpt(src, condition),
+			start:       condition.Start.Byte,
+			end:         condition.End.Byte,
+			replacement: "!(" + string(condition.SliceBytes(src)) + ")",
+		}}
+
+	case *hclsyntax.TupleConsExpr:
+		var mutants []Mutant
+		for i, element := range e.Exprs {
+			object, isObject := element.(*hclsyntax.ObjectConsExpr)
+			if !isObject {
+				continue
+			}
+			// The separator after the element is removed with it, or the one before the last element
+			start, end := element.Range().Start.Byte, element.Range().End.Byte
+			if i < len(e.Exprs)-1 {
+				end = e.Exprs[i+1].Range().Start.Byte
+			} else if i > 0 {
+				start = e.Exprs[i-1].Range().End.Byte
+			}
+			mutants = append(mutants, Mutant{
+				Line:        element.Range().Start.Line,
+				Operator:    OpDropElement,
+				Description: fmt.Sprintf("drop element %d of %d%s", i+1, len(e.Exprs), objectName(object)),
+				start:       start,
+				end:         end,
+			})
+		}
+		return mutants
+	}
+	return nil
+}
+
+// objectName describes an object by its literal name attribute, eg. the name of an environment variable
+func objectName(object *hclsyntax.ObjectConsExpr) string {
+	for _, item := range object.Items {
+		if hcl.ExprAsKeyword(item.KeyExpr) != "name" {
+			continue
+		}
+		value, diags := item.ValueExpr.Value(nil)
+		if !diags.HasErrors() && value.Type() == cty.String && !value.IsNull() {
+			return fmt.Sprintf(" (name = %q)", value.AsString())
+		}
+	}
+	return ""
+}
+
+// excerpt returns the source of a range on a single line, shortened to be read in a report
+func excerpt(src []byte, rng hcl.Range) string {
+	text := strings.Join(strings.Fields(string(rng.SliceBytes(src))), " ")
+	if len(text) > 60 {
+		text = text[:57] + "..."
+	}
+	return text
+}
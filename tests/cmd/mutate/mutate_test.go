@@ -0,0 +1,149 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testModule = `variable "dd_apm" {
+  type = object({ enabled = bool })
+  validation {
+    condition     = var.dd_apm.enabled == true || var.dd_apm.enabled == false
+    error_message = "unused"
+  }
+}
+
+locals {
+  is_apm_socket_mount = var.dd_apm.enabled && local.is_linux
+  base_env = [
+    {
+      name  = "ECS_FARGATE"
+      value = "true"
+    },
+    {
+      name  = "DD_APM_ENABLED"
+      value = local.is_apm_socket_mount ? "true" : "false"
+    },
+  ]
+}
+`
+
+// writeModule writes a repository holding a single module file
+func writeModule(t *testing.T) string {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "modules", "ecs_fargate"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "modules", "ecs_fargate", "datadog.tf"), []byte(testModule), 0o644))
+	return root
+}
+
+// TestFindMutants tests that every operator produces valid HCL, and variable blocks are not mutated
+func TestFindMutants(t *testing.T) {
+	root := writeModule(t)
+	mutants, err := FindMutants(root, "modules/ecs_fargate/datadog.tf")
+	require.NoError(t, err)
+
+	type summary struct {
+		ID                                      int
+		Line                                    int
+		Location, Operator, Description, Edited string
+	}
+	src := []byte(testModule)
+	var summaries []summary
+	for _, mutant := range mutants {
+		mutated := mutant.Apply(src)
+		_, diags := hclsyntax.ParseConfig(mutated, "datadog.tf", hcl.InitialPos)
+		assert.False(t, diags.HasErrors(), "Mutant %d does not parse: %s", mutant.ID, diags)
+		// The line holding the edit, to check what was changed
+		line := bytes.Split(mutated, []byte("\n"))[mutant.Line-1]
+		summaries = append(summaries, summary{mutant.ID, mutant.Line, mutant.Location, mutant.Operator, mutant.Description, string(bytes.TrimSpace(line))})
+	}
+	assert.Equal(t, []summary{
+		{1, 10, "locals.is_apm_socket_mount", OpFlipLogic, "&& -> || in var.dd_apm.enabled && local.is_linux", "is_apm_socket_mount = var.dd_apm.enabled || local.is_linux"},
+		{2, 12, "locals.base_env", OpDropElement, `drop element 1 of 2 (name = "ECS_FARGATE")`, "{"},
+		{3, 16, "locals.base_env", OpDropElement, `drop element 2 of 2 (name = "DD_APM_ENABLED")`, "]"},
+		{4, 18, "locals.base_env", OpNegateCondition, "negate local.is_apm_socket_mount", `value = !(local.is_apm_socket_mount) ? "true" : "false"`},
+	}, summaries)
+
+	assert.NotContains(t, string(mutants[1].Apply(src)), "ECS_FARGATE")
+	assert.NotContains(t, string(mutants[2].Apply(src)), "DD_APM_ENABLED")
+}
+
+// TestRun tests that mutants failing the command are killed, and those passing it survive
+func TestRun(t *testing.T) {
+	root := writeModule(t)
+	mutants, err := FindMutants(root, "")
+	require.NoError(t, err)
+	require.Len(t, mutants, 4)
+
+	var log bytes.Buffer
+	runner := &Runner{
+		Root: root,
+		// The "tests" only catch a missing ECS_FARGATE variable
+		Command:       []string{"sh", "-c", `grep -q ECS_FARGATE modules/ecs_fargate/datadog.tf || { echo "--- FAIL: TestEnv (0.00s)"; exit 1; }`},
+		MutantTimeout: time.Minute,
+		Parallel:      2,
+		Log:           &log,
+	}
+	require.NoError(t, runner.Baseline(context.Background()))
+	outcomes, err := runner.Run(context.Background(), mutants)
+	require.NoError(t, err)
+
+	results := map[int]Result{}
+	for _, outcome := range outcomes {
+		results[outcome.Mutant.ID] = outcome.Result
+	}
+	assert.Equal(t, map[int]Result{1: Survived, 2: Killed, 3: Survived, 4: Survived}, results)
+	assert.Equal(t, "TestEnv", outcomes[1].FailedBy)
+	assert.Contains(t, log.String(), "mutant 2/4 modules/ecs_fargate/datadog.tf:12 drop-element: killed")
+
+	// The file of the repository is never modified
+	data, err := os.ReadFile(filepath.Join(root, "modules", "ecs_fargate", "datadog.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, testModule, string(data))
+
+	var report bytes.Buffer
+	require.NoError(t, Report(&report, outcomes))
+	assert.Contains(t, report.String(), "4 mutants: 1 killed, 3 survived, 0 timed out, 0 not run")
+}
+
+// TestRunTimeouts tests that a mutant exceeding its timeout is reported, and that mutants are not run past the run timeout
+func TestRunTimeouts(t *testing.T) {
+	root := writeModule(t)
+	mutants, err := FindMutants(root, "")
+	require.NoError(t, err)
+
+	runner := &Runner{Root: root, Command: []string{"sleep", "10"}, MutantTimeout: 100 * time.Millisecond, Parallel: 1, Log: &bytes.Buffer{}}
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	outcomes, err := runner.Run(ctx, mutants)
+	require.NoError(t, err)
+	assert.Equal(t, TimedOut, outcomes[0].Result)
+	assert.Equal(t, NotRun, outcomes[len(outcomes)-1].Result)
+}
+
+// TestRunGoTestTimeout tests that a mutant stopped by the go test timeout is reported timed out, not killed
+func TestRunGoTestTimeout(t *testing.T) {
+	root := writeModule(t)
+	mutants, err := FindMutants(root, "")
+	require.NoError(t, err)
+
+	runner := &Runner{Root: root, Command: []string{"sh", "-c", `echo "panic: test timed out after 10m0s"; exit 2`}, MutantTimeout: time.Minute, Parallel: 1, Log: &bytes.Buffer{}}
+	outcomes, err := runner.Run(context.Background(), mutants[:1])
+	require.NoError(t, err)
+	assert.Equal(t, TimedOut, outcomes[0].Result)
+	assert.Empty(t, outcomes[0].FailedBy)
+}
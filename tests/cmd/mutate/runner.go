@@ -0,0 +1,258 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// Result is the outcome of running the tests against a mutant
+type Result string
+
+const (
+	// Killed mutants fail the tests
+	Killed Result = "killed"
+	// Survived mutants pass the tests: the edited logic is not tested
+	Survived Result = "survived"
+	// TimedOut mutants did not finish before the mutant timeout, eg. by breaking a retry loop
+	TimedOut Result = "timed out"
+	// NotRun mutants were not run before the timeout of the whole run
+	NotRun Result = "not run"
+)
+
+// Outcome is the result of a mutant, with the first failing test of killed mutants
+type Outcome struct {
+	Mutant   Mutant
+	Result   Result
+	FailedBy string
+	Duration time.Duration
+}
+
+// goTestTimeout starts the panic of a go test binary stopped by its -timeout
+const goTestTimeout = "panic: test timed out after"
+
+// skippedPaths are not copied to the working trees: the git history, terraform working files and scenario workspaces
+var skippedPaths = []string{".git", ".terraform", ".workspaces"}
+
+// Runner runs a test command against mutants, each in a copy of the repository
+type Runner struct {
+	// Root is the repository directory
+	Root string
+	// Command is the test command, run from the root of the copy
+	Command []string
+	// Env is added to the environment of the command
+	Env []string
+	// MutantTimeout bounds the run of the command against a single mutant
+	MutantTimeout time.Duration
+	// Parallel is the number of mutants run at the same time, each in its own copy
+	Parallel int
+	// Log receives a line per mutant run
+	Log io.Writer
+}
+
+// Baseline runs the command against the unmodified repository, which must pass for the mutants to be meaningful
+func (r *Runner) Baseline(ctx context.Context) error {
+	dir, err := r.copyRoot()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	output, err := r.runCommand(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("the tests fail without mutation, fix them first: %w\n%s", err, tail(output, 20))
+	}
+	return nil
+}
+
+// Run runs the command against every mutant and returns their outcome in the order of mutants.
+// Mutants not started before ctx is done are reported NotRun.
+func (r *Runner) Run(ctx context.Context, mutants []Mutant) ([]Outcome, error) {
+	outcomes := make([]Outcome, len(mutants))
+	for i, mutant := range mutants {
+		outcomes[i] = Outcome{Mutant: mutant, Result: NotRun}
+	}
+
+	jobs := make(chan int)
+	errs := make([]error, max(r.Parallel, 1))
+	var wg sync.WaitGroup
+	for worker := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dir, err := r.copyRoot()
+			if err != nil {
+				errs[worker] = err
+				for range jobs {
+				}
+				return
+			}
+			defer os.RemoveAll(dir)
+			for i := range jobs {
+				outcome, err := r.runMutant(ctx, dir, mutants[i])
+				if err != nil {
+					errs[worker] = err
+					continue
+				}
+				outcomes[i] = outcome
+				fmt.Fprintf(r.Log, "mutant %d/%d %s:%d %s: %s (%s)\n", mutants[i].ID, len(mutants), mutants[i].File, mutants[i].Line, mutants[i].Operator, outcome.Result, outcome.Duration.Round(time.Second))
+			}
+		}()
+	}
+
+	for i := range mutants {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return outcomes, errors.Join(errs...)
+}
+
+// runMutant applies the mutant to its file in dir, runs the command, then restores the file
+func (r *Runner) runMutant(ctx context.Context, dir string, mutant Mutant) (Outcome, error) {
+	path := filepath.Join(dir, mutant.File)
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return Outcome{}, err
+	}
+	if err := os.WriteFile(path, mutant.Apply(original), 0o644); err != nil {
+		return Outcome{}, err
+	}
+	defer os.WriteFile(path, original, 0o644)
+
+	mutantCtx, cancel := context.WithTimeout(ctx, r.MutantTimeout)
+	defer cancel()
+	start := time.Now()
+	output, err := r.runCommand(mutantCtx, dir)
+	outcome := Outcome{Mutant: mutant, Result: Survived, Duration: time.Since(start)}
+	switch {
+	case ctx.Err() != nil:
+		outcome.Result = NotRun
+	case mutantCtx.Err() != nil, bytes.Contains(output, []byte(goTestTimeout)):
+		outcome.Result = TimedOut
+	case err != nil:
+		outcome.Result = Killed
+		outcome.FailedBy = firstFailure(output)
+	}
+	return outcome, nil
+}
+
+// runCommand runs the command in dir and returns its combined output. The whole process group is killed
+// when ctx is done, so the test binaries and terraform started by the command do not outlive it.
+func (r *Runner) runCommand(ctx context.Context, dir string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, r.Command[0], r.Command[1:]...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), r.Env...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	cmd.WaitDelay = 10 * time.Second
+	return cmd.CombinedOutput()
+}
+
+// copyRoot copies the repository to a temporary directory
+func (r *Runner) copyRoot() (string, error) {
+	dir, err := os.MkdirTemp("", "terraform-mutant-")
+	if err != nil {
+		return "", err
+	}
+	err = filepath.WalkDir(r.Root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		for _, skipped := range skippedPaths {
+			if entry.Name() == skipped && path != r.Root {
+				return filepath.SkipDir
+			}
+		}
+		relative, err := filepath.Rel(r.Root, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, relative)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), "terraform.tfstate") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// firstFailure returns the name of the first failed test in go test output
+func firstFailure(output []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if name, found := strings.CutPrefix(line, "--- FAIL: "); found {
+			name, _, _ = strings.Cut(name, " ")
+			return name
+		}
+	}
+	return ""
+}
+
+// tail returns the last lines of an output
+func tail(output []byte, lines int) string {
+	all := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	return strings.Join(all[max(len(all)-lines, 0):], "\n")
+}
+
+// Report writes a line per mutant, the survivors first, then the number of mutants per result
+func Report(w io.Writer, outcomes []Outcome) error {
+	counts := map[Result]int{}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tRESULT\tLOCATION\tOPERATOR\tMUTATION\tFAILED TEST")
+	for _, result := range []Result{Survived, TimedOut, NotRun, Killed} {
+		for _, outcome := range outcomes {
+			if outcome.Result != result {
+				continue
+			}
+			counts[result]++
+			m := outcome.Mutant
+			fmt.Fprintf(tw, "%d\t%s\t%s:%d %s\t%s\t%s\t%s\n", m.ID, result, m.File, m.Line, m.Location, m.Operator, m.Description, outcome.FailedBy)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d mutants: %d killed, %d survived, %d timed out, %d not run\n",
+		len(outcomes), counts[Killed], counts[Survived], counts[TimedOut], counts[NotRun])
+	return err
+}
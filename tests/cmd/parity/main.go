@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Command parity compares the ecs_fargate and ecs_ec2 modules: their variables and the attributes nested
+// in them, the DD_* environment variables they can set, and the variables they declare but never read.
+// It writes the parity table and fails on the gaps not listed in the allowlist.
+//
+// Run it from the repository root:
+//
+//	go run ./tests/cmd/parity
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DataDog/terraform-ecs-datadog/tests/internal/moduleschema"
+)
+
+func main() {
+	modulesDir := flag.String("modules", "modules", "Directory of the modules")
+	allowlist := flag.String("allowlist", "tests/cmd/parity/allowlist.txt", "File listing the acknowledged gaps, as `kind name`")
+	all := flag.Bool("all", false, "Also list the features both modules support")
+	flag.Parse()
+
+	if err := run(*modulesDir, *allowlist, *all); err != nil {
+		fmt.Fprintln(os.Stderr, "parity:", err)
+		os.Exit(1)
+	}
+}
+
+func run(modulesDir, allowlist string, all bool) error {
+	report, err := CompareModules(modulesDir)
+	if err != nil {
+		return err
+	}
+	allowed, err := moduleschema.ReadAllowlist(allowlist)
+	if err != nil {
+		return err
+	}
+	if err := report.Write(os.Stdout, allowed, all); err != nil {
+		return err
+	}
+	return report.Check(allowed)
+}
+
+// CompareModules returns the parity table of the ecs_fargate and ecs_ec2 modules under modulesDir
+func CompareModules(modulesDir string) (*Report, error) {
+	fargate, err := LoadModule(filepath.Join(modulesDir, "ecs_fargate"))
+	if err != nil {
+		return nil, err
+	}
+	ec2, err := LoadModule(filepath.Join(modulesDir, "ecs_ec2"))
+	if err != nil {
+		return nil, err
+	}
+	return Compare(fargate, ec2), nil
+}
@@ -0,0 +1,247 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DataDog/terraform-ecs-datadog/tests/internal/moduleschema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Kinds of features compared between the modules
+const (
+	// KindVariable is a variable, or an attribute nested in its type
+	KindVariable = "variable"
+	// KindEnv is a DD_* environment variable the module can set
+	KindEnv = "env"
+	// KindUnused is a variable the module declares but never reads, outside of its own validations
+	KindUnused = "unused"
+)
+
+// envVarName matches the Datadog environment variable names among the string literals of a module
+var envVarName = regexp.MustCompile(`^DD_[A-Z0-9_]+$`)
+
+// Module holds the features of a module compared by the parity report
+type Module struct {
+	Name string
+	// Paths are the variables and the attributes nested in their types
+	Paths map[string]bool
+	// EnvVars are the DD_* names written as string literals in the module logic
+	EnvVars map[string]bool
+	// Unused are the variables never referenced outside of variable blocks
+	Unused map[string]bool
+}
+
+// LoadModule reads the features of a module directory
+func LoadModule(dir string) (*Module, error) {
+	module := &Module{Name: filepath.Base(dir), Paths: map[string]bool{}, EnvVars: map[string]bool{}, Unused: map[string]bool{}}
+	variables, err := moduleschema.Variables(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, variable := range variables {
+		for _, path := range moduleschema.Paths(variable.Name, variable.Type) {
+			module.Paths[path] = true
+		}
+		module.Unused[variable.Name] = true
+	}
+
+	bodies, err := moduleschema.ParseModule(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, body := range bodies {
+		for _, block := range body.Blocks {
+			if block.Type == "variable" {
+				continue
+			}
+			hclsyntax.VisitAll(block.Body, func(node hclsyntax.Node) hcl.Diagnostics {
+				module.visit(node)
+				return nil
+			})
+		}
+		for _, attribute := range body.Attributes {
+			hclsyntax.VisitAll(attribute.Expr, func(node hclsyntax.Node) hcl.Diagnostics {
+				module.visit(node)
+				return nil
+			})
+		}
+	}
+	return module, nil
+}
+
+// visit records the environment variable names and the variable references of a node of the module logic
+func (m *Module) visit(node hclsyntax.Node) {
+	switch e := node.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		if len(e.Traversal) > 1 && e.Traversal.RootName() == "var" {
+			if attribute, isAttribute := e.Traversal[1].(hcl.TraverseAttr); isAttribute {
+				delete(m.Unused, attribute.Name)
+			}
+		}
+	case *hclsyntax.TemplateExpr:
+		if !e.IsStringLiteral() {
+			return
+		}
+		value, diags := e.Value(nil)
+		if !diags.HasErrors() && value.Type() == cty.String && envVarName.MatchString(value.AsString()) {
+			m.EnvVars[value.AsString()] = true
+		}
+	}
+}
+
+// Row is a line of the parity table: a feature and how each module supports it
+type Row struct {
+	Kind string
+	Name string
+	// Support describes the feature in each module, in the order of the compared modules: yes, unused or -
+	Support []string
+}
+
+// Key identifies the row in the allowlist, eg. "env DD_LOG_LEVEL"
+func (r Row) Key() string {
+	return r.Kind + " " + r.Name
+}
+
+// Gap reports whether the modules differ on the feature
+func (r Row) Gap() bool {
+	return r.Kind == KindUnused || slices.Contains(r.Support, "-")
+}
+
+// Report is the parity table of modules
+type Report struct {
+	Modules []string
+	Rows    []Row
+}
+
+// Compare returns the parity table of the modules: their variable paths, environment variables and unused variables.
+// A variable missing from a module is reported once, without the attributes nested in it.
+func Compare(modules ...*Module) *Report {
+	report := &Report{}
+	for _, module := range modules {
+		report.Modules = append(report.Modules, module.Name)
+	}
+
+	support := func(has func(*Module) bool) []string {
+		cells := make([]string, len(modules))
+		for i, module := range modules {
+			cells[i] = "-"
+			if has(module) {
+				cells[i] = "yes"
+			}
+		}
+		return cells
+	}
+
+	var gapPaths []string
+	for _, path := range union(modules, func(m *Module) map[string]bool { return m.Paths }) {
+		row := Row{Kind: KindVariable, Name: path, Support: support(func(m *Module) bool { return m.Paths[path] })}
+		if row.Gap() && slices.ContainsFunc(gapPaths, func(parent string) bool { return isNested(path, parent) }) {
+			continue
+		}
+		if row.Gap() {
+			gapPaths = append(gapPaths, path)
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	for _, name := range union(modules, func(m *Module) map[string]bool { return m.EnvVars }) {
+		report.Rows = append(report.Rows, Row{Kind: KindEnv, Name: name, Support: support(func(m *Module) bool { return m.EnvVars[name] })})
+	}
+
+	for _, module := range modules {
+		for _, name := range sortedKeys(module.Unused) {
+			row := Row{Kind: KindUnused, Name: module.Name + "." + name, Support: support(func(m *Module) bool { return m.Paths[name] })}
+			for i, other := range modules {
+				if other.Unused[name] {
+					row.Support[i] = "unused"
+				}
+			}
+			report.Rows = append(report.Rows, row)
+		}
+	}
+	return report
+}
+
+// isNested reports whether path is an attribute nested in parent
+func isNested(path string, parent string) bool {
+	return strings.HasPrefix(path, parent+".") || strings.HasPrefix(path, parent+moduleschema.ElementPath)
+}
+
+// union returns the sorted keys of a set of every module
+func union(modules []*Module, set func(*Module) map[string]bool) []string {
+	all := map[string]bool{}
+	for _, module := range modules {
+		for key := range set(module) {
+			all[key] = true
+		}
+	}
+	return sortedKeys(all)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Write writes the parity table, with the allowlisted gaps marked. Rows without gap are only written when all is set.
+func (r *Report) Write(w io.Writer, allowed map[string]bool, all bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "KIND\tNAME\t%s\tSTATUS\n", strings.ToUpper(strings.Join(r.Modules, "\t")))
+	for _, row := range r.Rows {
+		status := "ok"
+		switch {
+		case row.Gap() && allowed[row.Key()]:
+			status = "allowlisted"
+		case row.Gap():
+			status = "GAP"
+		case !all:
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", row.Kind, row.Name, strings.Join(row.Support, "\t"), status)
+	}
+	return tw.Flush()
+}
+
+// Check returns an error listing the gaps missing from the allowlist, and the allowlisted gaps that were closed,
+// which must be removed from it
+func (r *Report) Check(allowed map[string]bool) error {
+	var errs []error
+	gaps := map[string]bool{}
+	for _, row := range r.Rows {
+		if !row.Gap() {
+			continue
+		}
+		gaps[row.Key()] = true
+		switch {
+		case allowed[row.Key()]:
+		case row.Kind == KindUnused:
+			errs = append(errs, fmt.Errorf("%s is declared but never read", row.Key()))
+		default:
+			errs = append(errs, fmt.Errorf("%s differs between the modules (%s: %s)", row.Key(), strings.Join(r.Modules, "/"), strings.Join(row.Support, "/")))
+		}
+	}
+	for _, key := range sortedKeys(allowed) {
+		if !gaps[key] {
+			errs = append(errs, fmt.Errorf("%s is allowlisted but is not a gap anymore, remove it from the allowlist", key))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/terraform-ecs-datadog/tests/internal/moduleschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFargate = `
+variable "dd_apm" {
+  type = object({ enabled = bool })
+}
+
+variable "dd_cws" {
+  type = object({
+    enabled = bool
+    cpu     = number
+  })
+}
+
+variable "dd_checks_cardinality" {
+  type = string
+  validation {
+    condition     = var.dd_checks_cardinality == null || var.dd_checks_cardinality == "low"
+    error_message = "DD_CHECKS_TAG_CARDINALITY must be low"
+  }
+}
+
+locals {
+  env = [
+    { name = "DD_APM_ENABLED", value = tostring(var.dd_apm.enabled) },
+    { name = "DD_RUNTIME_SECURITY_CONFIG_ENABLED", value = tostring(var.dd_cws.enabled) },
+    { name = "DD_${upper("site")}", value = "not a literal" },
+  ]
+}
+`
+
+const testEC2 = `
+variable "dd_apm" {
+  type = object({
+    enabled     = bool
+    tcp_enabled = bool
+  })
+}
+
+variable "dd_checks_cardinality" {
+  type = string
+}
+
+locals {
+  env = [
+    { name = "DD_APM_ENABLED", value = tostring(var.dd_apm.enabled && var.dd_apm.tcp_enabled) },
+    { name = "DD_CHECKS_TAG_CARDINALITY", value = var.dd_checks_cardinality },
+  ]
+}
+`
+
+// TestCompare tests the parity table of two modules and the check of its gaps against the allowlist
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"ecs_fargate": testFargate, "ecs_ec2": testEC2} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, "main.tf"), []byte(content), 0o644))
+	}
+	report, err := CompareModules(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Row{
+		{KindVariable, "dd_apm", []string{"yes", "yes"}},
+		{KindVariable, "dd_apm.enabled", []string{"yes", "yes"}},
+		{KindVariable, "dd_apm.tcp_enabled", []string{"-", "yes"}},
+		{KindVariable, "dd_checks_cardinality", []string{"yes", "yes"}},
+		{KindVariable, "dd_cws", []string{"yes", "-"}},
+		{KindEnv, "DD_APM_ENABLED", []string{"yes", "yes"}},
+		{KindEnv, "DD_CHECKS_TAG_CARDINALITY", []string{"-", "yes"}},
+		{KindEnv, "DD_RUNTIME_SECURITY_CONFIG_ENABLED", []string{"yes", "-"}},
+		{KindUnused, "ecs_fargate.dd_checks_cardinality", []string{"unused", "yes"}},
+	}, report.Rows)
+
+	allowed := map[string]bool{"variable dd_cws": true, "env DD_RUNTIME_SECURITY_CONFIG_ENABLED": true, "env DD_LOG_LEVEL": true}
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out, allowed, false))
+	assert.Equal(t, `KIND      NAME                                ECS_FARGATE  ECS_EC2  STATUS
+variable  dd_apm.tcp_enabled                  -            yes      GAP
+variable  dd_cws                              yes          -        allowlisted
+env       DD_CHECKS_TAG_CARDINALITY           -            yes      GAP
+env       DD_RUNTIME_SECURITY_CONFIG_ENABLED  yes          -        allowlisted
+unused    ecs_fargate.dd_checks_cardinality   unused       yes      GAP
+`, out.String())
+
+	assert.EqualError(t, report.Check(allowed), "variable dd_apm.tcp_enabled differs between the modules (ecs_fargate/ecs_ec2: -/yes)\n"+
+		"env DD_CHECKS_TAG_CARDINALITY differs between the modules (ecs_fargate/ecs_ec2: -/yes)\n"+
+		"unused ecs_fargate.dd_checks_cardinality is declared but never read\n"+
+		"env DD_LOG_LEVEL is allowlisted but is not a gap anymore, remove it from the allowlist")
+}
+
+// TestRepositoryParity fails on the differences between the modules missing from the allowlist
+func TestRepositoryParity(t *testing.T) {
+	report, err := CompareModules("../../../modules")
+	require.NoError(t, err)
+	allowed, err := moduleschema.ReadAllowlist("allowlist.txt")
+	require.NoError(t, err)
+	assert.NoError(t, report.Check(allowed), "Close the gaps, or acknowledge them in tests/cmd/parity/allowlist.txt")
+}
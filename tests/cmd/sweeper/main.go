@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Command sweeper deletes the resources leaked by interrupted smoke test runs: ECS services and task
+// definitions, IAM roles and policies named with the test prefix, optionally only those tagged by the modules.
+//
+// Run it against the test account only, first with -dry-run:
+//
+//	go run ./tests/cmd/sweeper -older-than 6h -dry-run
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+func main() {
+	prefix := flag.String("prefix", "terraform-test", "Name prefix of the test resources")
+	olderThan := flag.Duration("older-than", 6*time.Hour, "Minimum age of the resources to delete, younger ones may belong to a running test")
+	requireTag := flag.Bool("require-tag", false, "Only delete the prefixed resources that are also tagged "+ModuleTag)
+	dryRun := flag.Bool("dry-run", false, "Report the resources that would be deleted without deleting them")
+	region := flag.String("region", "us-east-1", "AWS region of the ECS resources")
+	endpointURL := flag.String("endpoint-url", "", "Endpoint of every AWS API, eg. a local stand-in")
+	flag.Parse()
+
+	if *prefix == "" {
+		fmt.Fprintln(os.Stderr, "sweeper: -prefix must not be empty")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, *prefix, *requireTag, *olderThan, *dryRun, *region, *endpointURL); err != nil {
+		fmt.Fprintln(os.Stderr, "sweeper:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, prefix string, requireTag bool, olderThan time.Duration, dryRun bool, region, endpointURL string) error {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return err
+	}
+	if endpointURL != "" {
+		cfg.BaseEndpoint = aws.String(endpointURL)
+	}
+
+	now := time.Now()
+	sweeper := &Sweeper{
+		ECS:        ecs.NewFromConfig(cfg),
+		IAM:        iam.NewFromConfig(cfg),
+		Prefix:     prefix,
+		RequireTag: requireTag,
+		Before:     now.Add(-olderThan),
+	}
+	resources, err := sweeper.Find(ctx)
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		fmt.Printf("No resource named %s* older than %s\n", prefix, olderThan)
+		return nil
+	}
+	if err := Report(os.Stdout, resources, now); err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Printf("Dry run: %d resources would be deleted\n", len(resources))
+		return nil
+	}
+	return sweeper.Sweep(ctx, resources, os.Stdout)
+}
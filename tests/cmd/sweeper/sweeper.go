@@ -0,0 +1,405 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// ModuleTag is the tag the modules set on the resources they create
+const ModuleTag = "dd_ecs_terraform_module"
+
+// Kind is the type of a swept resource. Kinds are declared in the order resources are swept:
+// services use task definitions, and roles hold the attachments of policies.
+type Kind int
+
+const (
+	KindService Kind = iota
+	KindTaskDefinition
+	KindRole
+	KindPolicy
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindService:
+		return "ecs-service"
+	case KindTaskDefinition:
+		return "ecs-task-definition"
+	case KindRole:
+		return "iam-role"
+	case KindPolicy:
+		return "iam-policy"
+	}
+	return fmt.Sprintf("kind(%d)", int(k))
+}
+
+// Resource is a leaked resource found by the sweeper
+type Resource struct {
+	Kind Kind
+	// Name is the service name, the task definition `family:revision`, or the role or policy name
+	Name string
+	// ID identifies the resource in the API calls: an ARN, or the name of a role
+	ID string
+	// Cluster is the ARN of the cluster of a service
+	Cluster string
+	// Active reports whether a task definition is still registered
+	Active  bool
+	Created time.Time
+}
+
+// Sweeper finds and deletes the resources left behind by interrupted test runs
+type Sweeper struct {
+	ECS *ecs.Client
+	IAM *iam.Client
+
+	// Prefix matches the names of the test resources
+	Prefix string
+	// RequireTag only sweeps the resources named with the prefix that are also tagged by the modules
+	RequireTag bool
+	// Before is the creation time a resource must predate to be swept
+	Before time.Time
+}
+
+// matches reports whether a resource is a test resource: named with the prefix, and tagged by the modules with RequireTag.
+// The tag alone does not match, the modules set it on every resource they create, test or not.
+func (s *Sweeper) matches(name string, tagged bool, created time.Time) bool {
+	return strings.HasPrefix(name, s.Prefix) && (tagged || !s.RequireTag) && created.Before(s.Before)
+}
+
+// Find lists the test resources older than the cutoff, in the order they must be deleted
+func (s *Sweeper) Find(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	for _, find := range []func(context.Context) ([]Resource, error){s.findServices, s.findTaskDefinitions, s.findRoles, s.findPolicies} {
+		found, err := find(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, found...)
+	}
+	return resources, nil
+}
+
+func (s *Sweeper) findServices(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	clusters := ecs.NewListClustersPaginator(s.ECS, &ecs.ListClustersInput{})
+	for clusters.HasMorePages() {
+		page, err := clusters.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing clusters: %w", err)
+		}
+		for _, cluster := range page.ClusterArns {
+			found, err := s.findClusterServices(ctx, cluster)
+			if err != nil {
+				return nil, err
+			}
+			resources = append(resources, found...)
+		}
+	}
+	return resources, nil
+}
+
+func (s *Sweeper) findClusterServices(ctx context.Context, cluster string) ([]Resource, error) {
+	var resources []Resource
+	services := ecs.NewListServicesPaginator(s.ECS, &ecs.ListServicesInput{Cluster: aws.String(cluster)})
+	for services.HasMorePages() {
+		page, err := services.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing services of %s: %w", cluster, err)
+		}
+		// DescribeServices takes up to 10 services
+		for arns := range slices.Chunk(page.ServiceArns, 10) {
+			described, err := s.ECS.DescribeServices(ctx, &ecs.DescribeServicesInput{
+				Cluster:  aws.String(cluster),
+				Services: arns,
+				Include:  []ecstypes.ServiceField{ecstypes.ServiceFieldTags},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("describing services of %s: %w", cluster, err)
+			}
+			for _, svc := range described.Services {
+				name := aws.ToString(svc.ServiceName)
+				if !s.matches(name, hasECSTag(svc.Tags), aws.ToTime(svc.CreatedAt)) {
+					continue
+				}
+				resources = append(resources, Resource{
+					Kind:    KindService,
+					Name:    name,
+					ID:      aws.ToString(svc.ServiceArn),
+					Cluster: cluster,
+					Created: aws.ToTime(svc.CreatedAt),
+				})
+			}
+		}
+	}
+	return resources, nil
+}
+
+func (s *Sweeper) findTaskDefinitions(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	for _, status := range []ecstypes.TaskDefinitionStatus{ecstypes.TaskDefinitionStatusActive, ecstypes.TaskDefinitionStatusInactive} {
+		arns := ecs.NewListTaskDefinitionsPaginator(s.ECS, &ecs.ListTaskDefinitionsInput{Status: status})
+		for arns.HasMorePages() {
+			page, err := arns.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("listing %s task definitions: %w", status, err)
+			}
+			for _, arn := range page.TaskDefinitionArns {
+				described, err := s.ECS.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+					TaskDefinition: aws.String(arn),
+					Include:        []ecstypes.TaskDefinitionField{ecstypes.TaskDefinitionFieldTags},
+				})
+				if err != nil {
+					return nil, fmt.Errorf("describing task definition %s: %w", arn, err)
+				}
+				td := described.TaskDefinition
+				if !s.matches(aws.ToString(td.Family), hasECSTag(described.Tags), aws.ToTime(td.RegisteredAt)) {
+					continue
+				}
+				resources = append(resources, Resource{
+					Kind:    KindTaskDefinition,
+					Name:    fmt.Sprintf("%s:%d", aws.ToString(td.Family), td.Revision),
+					ID:      arn,
+					Active:  status == ecstypes.TaskDefinitionStatusActive,
+					Created: aws.ToTime(td.RegisteredAt),
+				})
+			}
+		}
+	}
+	return resources, nil
+}
+
+func (s *Sweeper) findRoles(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	roles := iam.NewListRolesPaginator(s.IAM, &iam.ListRolesInput{})
+	for roles.HasMorePages() {
+		page, err := roles.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing roles: %w", err)
+		}
+		for _, role := range page.Roles {
+			name := aws.ToString(role.RoleName)
+			if !strings.HasPrefix(name, s.Prefix) || !aws.ToTime(role.CreateDate).Before(s.Before) {
+				continue
+			}
+			// ListRoles does not return tags, they are only read when they narrow the match
+			tagged := false
+			if s.RequireTag {
+				tags, err := s.IAM.ListRoleTags(ctx, &iam.ListRoleTagsInput{RoleName: role.RoleName})
+				if err != nil {
+					return nil, fmt.Errorf("listing tags of role %s: %w", name, err)
+				}
+				tagged = hasIAMTag(tags.Tags)
+			}
+			if s.matches(name, tagged, aws.ToTime(role.CreateDate)) {
+				resources = append(resources, Resource{Kind: KindRole, Name: name, ID: name, Created: aws.ToTime(role.CreateDate)})
+			}
+		}
+	}
+	return resources, nil
+}
+
+func (s *Sweeper) findPolicies(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+	policies := iam.NewListPoliciesPaginator(s.IAM, &iam.ListPoliciesInput{Scope: iamtypes.PolicyScopeTypeLocal})
+	for policies.HasMorePages() {
+		page, err := policies.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing policies: %w", err)
+		}
+		for _, policy := range page.Policies {
+			name := aws.ToString(policy.PolicyName)
+			if !strings.HasPrefix(name, s.Prefix) || !aws.ToTime(policy.CreateDate).Before(s.Before) {
+				continue
+			}
+			tagged := false
+			if s.RequireTag {
+				tags, err := s.IAM.ListPolicyTags(ctx, &iam.ListPolicyTagsInput{PolicyArn: policy.Arn})
+				if err != nil {
+					return nil, fmt.Errorf("listing tags of policy %s: %w", name, err)
+				}
+				tagged = hasIAMTag(tags.Tags)
+			}
+			if s.matches(name, tagged, aws.ToTime(policy.CreateDate)) {
+				resources = append(resources, Resource{Kind: KindPolicy, Name: name, ID: aws.ToString(policy.Arn), Created: aws.ToTime(policy.CreateDate)})
+			}
+		}
+	}
+	return resources, nil
+}
+
+func hasECSTag(tags []ecstypes.Tag) bool {
+	return slices.ContainsFunc(tags, func(tag ecstypes.Tag) bool { return aws.ToString(tag.Key) == ModuleTag })
+}
+
+func hasIAMTag(tags []iamtypes.Tag) bool {
+	return slices.ContainsFunc(tags, func(tag iamtypes.Tag) bool { return aws.ToString(tag.Key) == ModuleTag })
+}
+
+// Report writes the resources as a table, with their age at now
+func Report(w io.Writer, resources []Resource, now time.Time) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "KIND\tNAME\tCREATED\tAGE")
+	for _, r := range resources {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", r.Kind, r.Name, r.Created.UTC().Format(time.RFC3339), now.Sub(r.Created).Truncate(time.Minute))
+	}
+	return table.Flush()
+}
+
+// Sweep deletes the resources in order. A resource that cannot be deleted does not stop the others,
+// all the failures are returned together.
+func (s *Sweeper) Sweep(ctx context.Context, resources []Resource, log io.Writer) error {
+	sorted := slices.Clone(resources)
+	slices.SortStableFunc(sorted, func(a, b Resource) int { return int(a.Kind) - int(b.Kind) })
+
+	var errs []error
+	for _, r := range sorted {
+		var err error
+		switch r.Kind {
+		case KindService:
+			err = s.deleteService(ctx, r)
+		case KindTaskDefinition:
+			err = s.deleteTaskDefinition(ctx, r)
+		case KindRole:
+			err = s.deleteRole(ctx, r)
+		case KindPolicy:
+			err = s.deletePolicy(ctx, r)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("deleting %s %s: %w", r.Kind, r.Name, err))
+			fmt.Fprintf(log, "failed to delete %s %s: %v\n", r.Kind, r.Name, err)
+			continue
+		}
+		fmt.Fprintf(log, "deleted %s %s\n", r.Kind, r.Name)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Sweeper) deleteService(ctx context.Context, r Resource) error {
+	// Forcing the deletion drains the service without scaling it down first, which DAEMON services cannot be
+	_, err := s.ECS.DeleteService(ctx, &ecs.DeleteServiceInput{Cluster: aws.String(r.Cluster), Service: aws.String(r.ID), Force: aws.Bool(true)})
+	return err
+}
+
+func (s *Sweeper) deleteTaskDefinition(ctx context.Context, r Resource) error {
+	if r.Active {
+		if _, err := s.ECS.DeregisterTaskDefinition(ctx, &ecs.DeregisterTaskDefinitionInput{TaskDefinition: aws.String(r.ID)}); err != nil {
+			return err
+		}
+	}
+	deleted, err := s.ECS.DeleteTaskDefinitions(ctx, &ecs.DeleteTaskDefinitionsInput{TaskDefinitions: []string{r.ID}})
+	if err != nil {
+		return err
+	}
+	if len(deleted.Failures) > 0 {
+		return errors.New(aws.ToString(deleted.Failures[0].Reason))
+	}
+	return nil
+}
+
+// deleteRole removes everything attached to the role, which IAM requires before deleting it
+func (s *Sweeper) deleteRole(ctx context.Context, r Resource) error {
+	attached := iam.NewListAttachedRolePoliciesPaginator(s.IAM, &iam.ListAttachedRolePoliciesInput{RoleName: aws.String(r.ID)})
+	for attached.HasMorePages() {
+		page, err := attached.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, policy := range page.AttachedPolicies {
+			if _, err := s.IAM.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: aws.String(r.ID), PolicyArn: policy.PolicyArn}); err != nil {
+				return err
+			}
+		}
+	}
+
+	inline := iam.NewListRolePoliciesPaginator(s.IAM, &iam.ListRolePoliciesInput{RoleName: aws.String(r.ID)})
+	for inline.HasMorePages() {
+		page, err := inline.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, name := range page.PolicyNames {
+			if _, err := s.IAM.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{RoleName: aws.String(r.ID), PolicyName: aws.String(name)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	profiles := iam.NewListInstanceProfilesForRolePaginator(s.IAM, &iam.ListInstanceProfilesForRoleInput{RoleName: aws.String(r.ID)})
+	for profiles.HasMorePages() {
+		page, err := profiles.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, profile := range page.InstanceProfiles {
+			if _, err := s.IAM.RemoveRoleFromInstanceProfile(ctx, &iam.RemoveRoleFromInstanceProfileInput{RoleName: aws.String(r.ID), InstanceProfileName: profile.InstanceProfileName}); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := s.IAM.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String(r.ID)})
+	return err
+}
+
+// deletePolicy detaches the policy from the entities that are not swept, such as roles given to the modules,
+// and deletes its non default versions, which IAM requires before deleting it
+func (s *Sweeper) deletePolicy(ctx context.Context, r Resource) error {
+	entities := iam.NewListEntitiesForPolicyPaginator(s.IAM, &iam.ListEntitiesForPolicyInput{PolicyArn: aws.String(r.ID)})
+	for entities.HasMorePages() {
+		page, err := entities.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, role := range page.PolicyRoles {
+			if _, err := s.IAM.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: role.RoleName, PolicyArn: aws.String(r.ID)}); err != nil {
+				return err
+			}
+		}
+		for _, user := range page.PolicyUsers {
+			if _, err := s.IAM.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{UserName: user.UserName, PolicyArn: aws.String(r.ID)}); err != nil {
+				return err
+			}
+		}
+		for _, group := range page.PolicyGroups {
+			if _, err := s.IAM.DetachGroupPolicy(ctx, &iam.DetachGroupPolicyInput{GroupName: group.GroupName, PolicyArn: aws.String(r.ID)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	versions := iam.NewListPolicyVersionsPaginator(s.IAM, &iam.ListPolicyVersionsInput{PolicyArn: aws.String(r.ID)})
+	for versions.HasMorePages() {
+		page, err := versions.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, version := range page.Versions {
+			if version.IsDefaultVersion {
+				continue
+			}
+			if _, err := s.IAM.DeletePolicyVersion(ctx, &iam.DeletePolicyVersionInput{PolicyArn: aws.String(r.ID), VersionId: version.VersionId}); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := s.IAM.DeletePolicy(ctx, &iam.DeletePolicyInput{PolicyArn: aws.String(r.ID)})
+	return err
+}
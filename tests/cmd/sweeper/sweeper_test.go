@@ -0,0 +1,181 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DataDog/terraform-ecs-datadog/tests/fakeaws"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSweeper returns a sweeper of the terraform-test resources calling a local AWS stand-in
+func newTestSweeper(t *testing.T) *Sweeper {
+	server := httptest.NewServer(fakeaws.New())
+	t.Cleanup(server.Close)
+
+	cfg := aws.Config{
+		Region: fakeaws.DefaultRegion,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		}),
+		BaseEndpoint: aws.String(server.URL),
+	}
+	return &Sweeper{ECS: ecs.NewFromConfig(cfg), IAM: iam.NewFromConfig(cfg), Prefix: "terraform-test"}
+}
+
+// createLeakedResources creates what an interrupted smoke test leaves behind, next to resources that must be kept
+func createLeakedResources(t *testing.T, s *Sweeper) {
+	ctx := context.Background()
+	register := func(family string, tags ...ecstypes.Tag) {
+		_, err := s.ECS.RegisterTaskDefinition(ctx, &ecs.RegisterTaskDefinitionInput{
+			Family:               aws.String(family),
+			ContainerDefinitions: []ecstypes.ContainerDefinition{{Name: aws.String("datadog-agent"), Image: aws.String("public.ecr.aws/datadog/agent:latest")}},
+			Tags:                 tags,
+		})
+		require.NoError(t, err)
+	}
+	moduleTag := ecstypes.Tag{Key: aws.String(ModuleTag), Value: aws.String("1.1.1")}
+
+	register("terraform-test-ci-all-dd-inputs", moduleTag)
+	register("terraform-test-ci-all-dd-inputs", moduleTag)
+	_, err := s.ECS.DeregisterTaskDefinition(ctx, &ecs.DeregisterTaskDefinitionInput{TaskDefinition: aws.String("terraform-test-ci-all-dd-inputs:1")})
+	require.NoError(t, err)
+	register("renamed-family", moduleTag)
+	register("customer-app")
+
+	_, err = s.ECS.CreateService(ctx, &ecs.CreateServiceInput{
+		ServiceName:        aws.String("terraform-test-ci-datadog-agent"),
+		Cluster:            aws.String("terraform-test-cluster"),
+		TaskDefinition:     aws.String("terraform-test-ci-all-dd-inputs"),
+		SchedulingStrategy: ecstypes.SchedulingStrategyDaemon,
+	})
+	require.NoError(t, err)
+
+	trustPolicy := aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ecs-tasks.amazonaws.com"},"Action":"sts:AssumeRole"}]}`)
+	for _, name := range []string{"terraform-test-ci-ecs-task-exec-role", "existing-exec-role"} {
+		_, err = s.IAM.CreateRole(ctx, &iam.CreateRoleInput{RoleName: aws.String(name), AssumeRolePolicyDocument: trustPolicy})
+		require.NoError(t, err)
+	}
+	// The modules tag the roles they create outside of the tests too
+	_, err = s.IAM.CreateRole(ctx, &iam.CreateRoleInput{
+		RoleName:                 aws.String("datadog-agent-task-role"),
+		AssumeRolePolicyDocument: trustPolicy,
+		Tags:                     []iamtypes.Tag{{Key: aws.String(ModuleTag), Value: aws.String("1.1.1")}},
+	})
+	require.NoError(t, err)
+
+	document := aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"secretsmanager:GetSecretValue","Resource":"*"}]}`)
+	policy, err := s.IAM.CreatePolicy(ctx, &iam.CreatePolicyInput{PolicyName: aws.String("terraform-test-ci-dd-secret-access"), PolicyDocument: document})
+	require.NoError(t, err)
+	_, err = s.IAM.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{PolicyArn: policy.Policy.Arn, PolicyDocument: document, SetAsDefault: true})
+	require.NoError(t, err)
+	for _, role := range []string{"terraform-test-ci-ecs-task-exec-role", "existing-exec-role"} {
+		_, err = s.IAM.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{RoleName: aws.String(role), PolicyArn: policy.Policy.Arn})
+		require.NoError(t, err)
+	}
+	_, err = s.IAM.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+		RoleName:  aws.String("terraform-test-ci-ecs-task-exec-role"),
+		PolicyArn: aws.String("arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"),
+	})
+	require.NoError(t, err)
+
+	_, err = s.IAM.CreatePolicy(ctx, &iam.CreatePolicyInput{PolicyName: aws.String("customer-policy"), PolicyDocument: document})
+	require.NoError(t, err)
+}
+
+// TestFindSkipsRecentResources tests that resources younger than the cutoff are left to the tests that may still use them
+func TestFindSkipsRecentResources(t *testing.T) {
+	s := newTestSweeper(t)
+	createLeakedResources(t, s)
+
+	s.Before = time.Now().Add(-time.Hour)
+	resources, err := s.Find(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, resources)
+}
+
+// TestSweep tests that the test resources are found in dependency order, reported, then deleted without touching the others
+func TestSweep(t *testing.T) {
+	s := newTestSweeper(t)
+	createLeakedResources(t, s)
+	ctx := context.Background()
+
+	s.Before = time.Now().Add(time.Minute)
+	resources, err := s.Find(ctx)
+	require.NoError(t, err)
+
+	var found []string
+	for _, r := range resources {
+		found = append(found, r.Kind.String()+" "+r.Name)
+	}
+	assert.Equal(t, []string{
+		"ecs-service terraform-test-ci-datadog-agent",
+		"ecs-task-definition terraform-test-ci-all-dd-inputs:2",
+		"ecs-task-definition terraform-test-ci-all-dd-inputs:1",
+		"iam-role terraform-test-ci-ecs-task-exec-role",
+		"iam-policy terraform-test-ci-dd-secret-access",
+	}, found)
+
+	var report bytes.Buffer
+	require.NoError(t, Report(&report, resources, s.Before))
+	assert.Contains(t, report.String(), "KIND")
+	assert.Regexp(t, `iam-role\s+terraform-test-ci-ecs-task-exec-role\s+\S+\s+1m0s`, report.String())
+
+	var log bytes.Buffer
+	require.NoError(t, s.Sweep(ctx, resources, &log))
+	assert.Contains(t, log.String(), "deleted iam-policy terraform-test-ci-dd-secret-access")
+
+	remaining, err := s.Find(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, remaining, "Every test resource should be deleted")
+
+	_, err = s.ECS.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("customer-app")})
+	assert.NoError(t, err, "Untagged task definitions without the prefix should be kept")
+	renamed, err := s.ECS.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("renamed-family")})
+	if assert.NoError(t, err, "Tagged task definitions without the prefix should be kept") {
+		assert.Equal(t, ecstypes.TaskDefinitionStatusActive, renamed.TaskDefinition.Status)
+	}
+	_, err = s.IAM.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String("datadog-agent-task-role")})
+	assert.NoError(t, err, "Tagged roles without the prefix should be kept")
+	attached, err := s.IAM.ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{RoleName: aws.String("existing-exec-role")})
+	require.NoError(t, err, "Roles without the prefix should be kept")
+	assert.Empty(t, attached.AttachedPolicies, "Swept policies should be detached from the kept roles")
+	policies, err := s.IAM.ListPolicies(ctx, &iam.ListPoliciesInput{Scope: iamtypes.PolicyScopeTypeLocal})
+	require.NoError(t, err)
+	require.Len(t, policies.Policies, 1)
+	assert.Equal(t, "customer-policy", aws.ToString(policies.Policies[0].PolicyName))
+}
+
+// TestFindRequireTag tests that RequireTag narrows the prefixed resources to those tagged by the modules
+func TestFindRequireTag(t *testing.T) {
+	s := newTestSweeper(t)
+	createLeakedResources(t, s)
+
+	s.RequireTag = true
+	s.Before = time.Now().Add(time.Minute)
+	resources, err := s.Find(context.Background())
+	require.NoError(t, err)
+
+	var found []string
+	for _, r := range resources {
+		found = append(found, r.Kind.String()+" "+r.Name)
+	}
+	assert.Equal(t, []string{
+		"ecs-task-definition terraform-test-ci-all-dd-inputs:2",
+		"ecs-task-definition terraform-test-ci-all-dd-inputs:1",
+	}, found)
+}
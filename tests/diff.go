@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
+)
+
+// LogContainerDiffFromGolden logs how the containers of a task differ from its golden file.
+// The suites call it when a test fails to show every change at once, beyond the first failed assertion.
+func LogContainerDiffFromGolden(t *testing.T, path string, task TaskDefinitionOutput, testPrefix string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Logf("Cannot compare the container definitions to their golden file: %v", err)
+		return
+	}
+	var expected GoldenTaskDefinition
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Logf("Failed to parse golden file %s: %v", path, err)
+		return
+	}
+
+	normalized, err := NewGoldenTaskDefinition(task, testPrefix)
+	if err != nil {
+		t.Logf("Failed to normalize the task definition: %v", err)
+		return
+	}
+	var actual GoldenTaskDefinition
+	if err := json.Unmarshal(normalized, &actual); err != nil {
+		t.Logf("Failed to parse the normalized task definition: %v", err)
+		return
+	}
+
+	t.Logf("Container definitions compared to golden file %s:\n%s", path, ecsassert.DiffContainerDefinitions(expected.ContainerDefinitions, actual.ContainerDefinitions))
+}
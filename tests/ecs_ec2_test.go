@@ -7,81 +7,161 @@ package test
 
 import (
 	"log"
-	"os"
 	"testing"
 
-	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/stretchr/testify/suite"
 )
 
 // ECSEC2Suite defines the test suite for ECS EC2
 type ECSEC2Suite struct {
-	suite.Suite
-	terraformOptions *terraform.Options
-	testPrefix       string
+	ecsSuite[EC2TaskOutput]
 }
 
 // TODO: Separate tests into different package for each tf module
 // TestECSEC2Suite is the entry point for the test suite
 func TestECSEC2Suite(t *testing.T) {
-	suite.Run(t, new(ECSEC2Suite))
+	suite.Run(t, &ECSEC2Suite{ecsSuite[EC2TaskOutput]{
+		smokeTest: "ecs_ec2",
+		// Use terraform binary instead of tofu
+		terraformBinary: "terraform",
+		decode:          DecodeEC2TaskOutput,
+	}})
 }
 
-// SetupSuite is run once at the beginning of the test suite
-func (s *ECSEC2Suite) SetupSuite() {
-	log.Println("Setting up ECS EC2 test suite resources...")
+// assertTaskArn checks the task ARN refers to the expected family, once known after apply
+func (s *ECSEC2Suite) assertTaskArn(task EC2TaskOutput, family string) {
+	if s.planOnly {
+		return
+	}
+	s.NotEmpty(task.Arn, "Task ARN should not be empty")
+	s.Contains(task.Arn, ":task-definition/"+family+":", "Task ARN should contain the correct family name")
+}
 
-	// All resources must be prefixed with terraform-test
-	s.testPrefix = "terraform-test"
-	ciJobID := os.Getenv("CI_JOB_ID")
-	if ciJobID != "" {
-		s.testPrefix = s.testPrefix + "-" + ciJobID
+// agentContainer returns the datadog-agent container of a task, failing the test if it is missing
+func (s *ECSEC2Suite) agentContainer(task EC2TaskOutput) types.ContainerDefinition {
+	agent, found := ecsassert.GetContainer(task.ContainerDefinitions, "datadog-agent")
+	s.Require().True(found, "Container datadog-agent not found in definitions")
+	return agent
+}
+
+// assertHostVolumes checks the host volumes the agent reads Docker and host metrics from, always mounted read-only
+func (s *ECSEC2Suite) assertHostVolumes(task EC2TaskOutput, agent types.ContainerDefinition) {
+	expectedHostPaths := map[string]string{
+		"docker_sock": "/var/run/docker.sock",
+		"proc":        "/proc/",
+		"cgroup":      "/sys/fs/cgroup/",
+	}
+	for name, hostPath := range expectedHostPaths {
+		volume, found := ecsassert.GetVolume(task.Volumes, name)
+		if s.True(found, "Volume %s not found in task definition", name) {
+			s.Require().NotNil(volume.Host, "Volume %s should be a host volume", name)
+			s.Equal(hostPath, aws.ToString(volume.Host.SourcePath), "Unexpected host path for volume %s", name)
+		}
 	}
 
-	// Define the Terraform options for the suite
-	s.terraformOptions = &terraform.Options{
-		// Path to the smoke_tests directory
-		TerraformDir: "../smoke_tests/ecs_ec2",
-		// Use terraform binary instead of tofu
-		TerraformBinary: "terraform",
-		// Variables to pass to the Terraform module
-		Vars: map[string]interface{}{
-			"dd_api_key":  "test-api-key",
-			"dd_site":     "datadoghq.com",
-			"test_prefix": s.testPrefix,
-		},
-		RetryableTerraformErrors: map[string]string{
-			"couldn't find resource": "terratest could not find the resource. check for access denied errors in cloudtrail",
-		},
+	ecsassert.AssertMountPoint(s.T(), agent, ecsassert.MountDockerSock)
+	ecsassert.AssertMountPoint(s.T(), agent, ecsassert.MountProc)
+	ecsassert.AssertMountPoint(s.T(), agent, ecsassert.MountCgroup)
+}
+
+// assertLogVolumes checks the pointdir and containers_root volumes are mounted only when log collection is enabled
+func (s *ECSEC2Suite) assertLogVolumes(task EC2TaskOutput, agent types.ContainerDefinition, logsEnabled bool) {
+	expectedHostPaths := map[string]string{
+		"pointdir":        "/opt/datadog-agent/run",
+		"containers_root": "/var/lib/docker/containers/",
+	}
+	for name, hostPath := range expectedHostPaths {
+		volume, found := ecsassert.GetVolume(task.Volumes, name)
+		s.Equal(logsEnabled, found, "Volume %s should only exist when log collection is enabled", name)
+		if found && s.NotNil(volume.Host, "Volume %s should be a host volume", name) {
+			s.Equal(hostPath, aws.ToString(volume.Host.SourcePath), "Unexpected host path for volume %s", name)
+		}
 	}
 
-	// Run terraform init and apply
-	terraform.InitAndApply(s.T(), s.terraformOptions)
+	if logsEnabled {
+		ecsassert.AssertMountPoint(s.T(), agent, ecsassert.MountPointdir)
+		ecsassert.AssertMountPoint(s.T(), agent, ecsassert.MountContainersRoot)
+		return
+	}
+	for _, mount := range agent.MountPoints {
+		source := aws.ToString(mount.SourceVolume)
+		s.NotContains([]string{"pointdir", "containers_root"}, source, "Log volume %s should not be mounted without log collection", source)
+	}
 }
 
-// TearDownSuite is run once at the end of the test suite
-func (s *ECSEC2Suite) TearDownSuite() {
-	log.Println("Tearing down ECS EC2 test suite resources...")
-	terraform.Destroy(s.T(), s.terraformOptions)
+// assertPortMappings checks the agent only exposes the DogStatsD and APM ports when their TCP transport is enabled
+func (s *ECSEC2Suite) assertPortMappings(agent types.ContainerDefinition, dogstatsdTCP bool, apmTCP bool) {
+	expected := []types.PortMapping{}
+	if dogstatsdTCP {
+		expected = append(expected, ecsassert.PortUDP)
+		ecsassert.AssertPortMapping(s.T(), agent, ecsassert.PortUDP)
+	}
+	if apmTCP {
+		expected = append(expected, ecsassert.PortTCP)
+		ecsassert.AssertPortMapping(s.T(), agent, ecsassert.PortTCP)
+	}
+	s.Len(agent.PortMappings, len(expected), "Unexpected number of port mappings in datadog-agent container")
 }
 
 // TestAgentOnly tests the basic agent-only deployment
 func (s *ECSEC2Suite) TestAgentOnly() {
 	log.Println("TestAgentOnly: Running test...")
 
-	// Retrieve the task ARN for the "agent-only" module
-	taskArn := terraform.Output(s.T(), s.terraformOptions, "agent_only_task_arn")
-	s.Contains(taskArn, s.testPrefix+"-agent-only", "Task ARN should contain the correct family name")
-	s.NotEmpty(taskArn, "Task ARN should not be empty")
+	// Retrieve the task output for the "agent-only" module
+	task := s.taskOutput("agent_only")
+	s.Equal(s.testPrefix+"-agent-only", task.Family, "Unexpected task family name")
+	s.assertTaskArn(task, s.testPrefix+"-agent-only")
+
+	s.Equal(1, len(task.ContainerDefinitions), "Expected 1 container in the task definition")
+	agent := s.agentContainer(task)
+	s.assertHostVolumes(task, agent)
+	s.assertLogVolumes(task, agent, false)
+
+	// DogStatsD and APM are enabled by default, over both UDS and TCP
+	ecsassert.AssertMountPoint(s.T(), agent, ecsassert.MountDdSocket)
+	s.assertPortMappings(agent, true, true)
+	ecsassert.AssertEnvVars(s.T(), agent, map[string]string{
+		"DD_API_KEY":                     "test-api-key",
+		"DD_SITE":                        "datadoghq.com",
+		"DD_DOGSTATSD_NON_LOCAL_TRAFFIC": "true",
+		"DD_APM_NON_LOCAL_TRAFFIC":       "true",
+		"DD_APM_ENABLED":                 "true",
+	})
+	ecsassert.AssertNotEnvVars(s.T(), agent, []string{
+		"DD_LOGS_ENABLED",
+		"DD_CONTAINER_INCLUDE_LOGS",
+		"DD_CONTAINER_EXCLUDE_LOGS",
+	})
 }
 
 // TestAllFeatures tests the all-features deployment
 func (s *ECSEC2Suite) TestAllFeatures() {
 	log.Println("TestAllFeatures: Running test...")
 
-	// Retrieve the task ARN for the "all-features" module
-	taskArn := terraform.Output(s.T(), s.terraformOptions, "all_features_task_arn")
-	s.Contains(taskArn, s.testPrefix+"-all-features", "Task ARN should contain the correct family name")
+	// Retrieve the task output for the "all-features" module
+	task := s.taskOutput("all_features")
+	s.Equal(s.testPrefix+"-all-features", task.Family, "Unexpected task family name")
+	s.assertTaskArn(task, s.testPrefix+"-all-features")
+
+	agent := s.agentContainer(task)
+	s.assertHostVolumes(task, agent)
+	s.assertLogVolumes(task, agent, true)
+	s.assertPortMappings(agent, true, true)
+
+	// Container filters are joined with spaces
+	ecsassert.AssertEnvVars(s.T(), agent, map[string]string{
+		"DD_DOGSTATSD_NON_LOCAL_TRAFFIC":       "true",
+		"DD_APM_NON_LOCAL_TRAFFIC":             "true",
+		"DD_DOGSTATSD_TAG_CARDINALITY":         "high",
+		"DD_CHECKS_TAG_CARDINALITY":            "orchestrator",
+		"DD_LOGS_ENABLED":                      "true",
+		"DD_LOGS_CONFIG_CONTAINER_COLLECT_ALL": "true",
+		"DD_CONTAINER_INCLUDE_LOGS":            "name:app image:nginx",
+		"DD_CONTAINER_EXCLUDE_LOGS":            "name:datadog-agent",
+	})
 }
 
 // TestBridgeNetworking tests bridge networking mode
@@ -89,11 +169,14 @@ func (s *ECSEC2Suite) TestBridgeNetworking() {
 	log.Println("TestBridgeNetworking: Running test...")
 
 	// Verify bridge network mode is set correctly
-	networkMode := terraform.Output(s.T(), s.terraformOptions, "bridge_mode_network_mode")
-	s.Equal("bridge", networkMode, "Network mode should be bridge")
-
-	taskArn := terraform.Output(s.T(), s.terraformOptions, "bridge_mode_task_arn")
-	s.Contains(taskArn, s.testPrefix+"-bridge-mode", "Task ARN should contain the correct family name")
+	task := s.taskOutput("bridge_mode")
+	s.Equal(types.NetworkModeBridge, task.NetworkMode, "Network mode should be bridge")
+	s.assertTaskArn(task, s.testPrefix+"-bridge-mode")
+
+	// Application containers reach the agent through the host ports in bridge mode
+	agent := s.agentContainer(task)
+	s.assertHostVolumes(task, agent)
+	s.assertPortMappings(agent, true, true)
 }
 
 // TestHostNetworking tests host networking mode
@@ -101,9 +184,48 @@ func (s *ECSEC2Suite) TestHostNetworking() {
 	log.Println("TestHostNetworking: Running test...")
 
 	// Verify host network mode is set correctly
-	networkMode := terraform.Output(s.T(), s.terraformOptions, "host_mode_network_mode")
-	s.Equal("host", networkMode, "Network mode should be host")
+	task := s.taskOutput("host_mode")
+	s.Equal(types.NetworkModeHost, task.NetworkMode, "Network mode should be host")
+	s.assertTaskArn(task, s.testPrefix+"-host-mode")
+
+	agent := s.agentContainer(task)
+	s.assertHostVolumes(task, agent)
+	s.assertPortMappings(agent, true, true)
+}
+
+// TestTCPEnabled tests DogStatsD and APM over TCP only
+func (s *ECSEC2Suite) TestTCPEnabled() {
+	log.Println("TestTCPEnabled: Running test...")
+
+	task := s.taskOutput("tcp_enabled")
+	s.Equal(s.testPrefix+"-tcp-enabled", task.Family, "Unexpected task family name")
+
+	agent := s.agentContainer(task)
+	s.assertPortMappings(agent, true, true)
+
+	// Without any socket, the dd-sockets volume is not created
+	_, found := ecsassert.GetVolume(task.Volumes, "dd-sockets")
+	s.False(found, "Volume dd-sockets should not exist without UDS")
+	for _, mount := range agent.MountPoints {
+		s.NotEqual("dd-sockets", aws.ToString(mount.SourceVolume), "Volume dd-sockets should not be mounted without UDS")
+	}
+}
+
+// TestSocketOnly tests DogStatsD and APM over UDS only
+func (s *ECSEC2Suite) TestSocketOnly() {
+	log.Println("TestSocketOnly: Running test...")
+
+	task := s.taskOutput("socket_only")
+	s.Equal(s.testPrefix+"-socket-only", task.Family, "Unexpected task family name")
+
+	// No port is exposed when both TCP transports are disabled
+	agent := s.agentContainer(task)
+	s.assertPortMappings(agent, false, false)
+	ecsassert.AssertMountPoint(s.T(), agent, ecsassert.MountDdSocket)
 
-	taskArn := terraform.Output(s.T(), s.terraformOptions, "host_mode_task_arn")
-	s.Contains(taskArn, s.testPrefix+"-host-mode", "Task ARN should contain the correct family name")
+	// Non-local traffic follows the enabled flags, not the transport
+	ecsassert.AssertEnvVars(s.T(), agent, map[string]string{
+		"DD_DOGSTATSD_NON_LOCAL_TRAFFIC": "true",
+		"DD_APM_NON_LOCAL_TRAFFIC":       "true",
+	})
 }
@@ -0,0 +1,192 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package ecsassert
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// GetContainer retrieves a container definition by name
+func GetContainer(containers []types.ContainerDefinition, name string) (types.ContainerDefinition, bool) {
+	for _, container := range containers {
+		if container.Name != nil && aws.ToString(container.Name) == name {
+			return container, true
+		}
+	}
+	return types.ContainerDefinition{}, false
+}
+
+// GetVolume retrieves a task definition volume by name
+func GetVolume(volumes []types.Volume, name string) (types.Volume, bool) {
+	for _, volume := range volumes {
+		if volume.Name != nil && *volume.Name == name {
+			return volume, true
+		}
+	}
+	return types.Volume{}, false
+}
+
+// GetEnvVar retrieves the value of an environment variable from a container definition
+func GetEnvVar(container types.ContainerDefinition, name string) (string, bool) {
+	for _, env := range container.Environment {
+		if env.Name != nil && env.Value != nil && *env.Name == name {
+			return *env.Value, true
+		}
+	}
+	return "", false
+}
+
+// AssertEnvVars checks if the expected environment variables are all present in the container
+func AssertEnvVars(t testing.TB, container types.ContainerDefinition, expectedEnvVars map[string]string) {
+	t.Helper()
+	assert.NotNil(t, container.Name, "Container name cannot be nil")
+
+	expected := container
+	expected.Environment = slices.Clone(container.Environment)
+	matched := true
+	for key, expectedValue := range expectedEnvVars {
+		value, found := GetEnvVar(container, key)
+		matched = assert.True(t, found, "Environment variable %s not found in %s container", key, aws.ToString(container.Name)) && matched
+		matched = assert.Equal(t, expectedValue, value, "Environment variable %s value does not match expected in %s container", key, aws.ToString(container.Name)) && matched
+		expected.Environment = slices.DeleteFunc(expected.Environment, func(env types.KeyValuePair) bool { return aws.ToString(env.Name) == key })
+		expected.Environment = append(expected.Environment, types.KeyValuePair{Name: aws.String(key), Value: aws.String(expectedValue)})
+	}
+	logContainerDiff(t, matched, expected, container)
+}
+
+// AssertNotEnvVars checks that a container does NOT have the specified environment variables
+func AssertNotEnvVars(t testing.TB, container types.ContainerDefinition, unexpectedEnvVars []string) {
+	t.Helper()
+	expected := container
+	matched := true
+	for _, unexpectedValue := range unexpectedEnvVars {
+		_, found := GetEnvVar(container, unexpectedValue)
+		matched = assert.False(t, found, "Environment variable %s should not be present in %s container", unexpectedValue, aws.ToString(container.Name)) && matched
+	}
+	expected.Environment = slices.DeleteFunc(slices.Clone(container.Environment), func(env types.KeyValuePair) bool {
+		return slices.Contains(unexpectedEnvVars, aws.ToString(env.Name))
+	})
+	logContainerDiff(t, matched, expected, container)
+}
+
+// AssertPortMapping checks if an expected port mapping exists in the container
+func AssertPortMapping(t testing.TB, container types.ContainerDefinition, expectedMapping types.PortMapping) {
+	t.Helper()
+	assert.NotNil(t, container.Name, "Container name cannot be nil")
+	assert.NotNil(t, expectedMapping.ContainerPort, "Expected container port cannot be nil")
+	assert.NotNil(t, expectedMapping.HostPort, "Expected host port cannot be nil")
+
+	found := false
+	for _, mapping := range container.PortMappings {
+		if mapping.ContainerPort != nil && mapping.HostPort != nil &&
+			*mapping.ContainerPort == aws.ToInt32(expectedMapping.ContainerPort) &&
+			*mapping.HostPort == aws.ToInt32(expectedMapping.HostPort) &&
+			mapping.Protocol == expectedMapping.Protocol {
+			found = true
+			break
+		}
+	}
+	matched := assert.True(t, found, "Expected port mapping (container:%d, host:%d, protocol:%s) not found in %s container",
+		aws.ToInt32(expectedMapping.ContainerPort), aws.ToInt32(expectedMapping.HostPort), expectedMapping.Protocol, aws.ToString(container.Name))
+
+	expected := container
+	expected.PortMappings = slices.DeleteFunc(slices.Clone(container.PortMappings), func(mapping types.PortMapping) bool {
+		return aws.ToInt32(mapping.ContainerPort) == aws.ToInt32(expectedMapping.ContainerPort) && mapping.Protocol == expectedMapping.Protocol
+	})
+	expected.PortMappings = append(expected.PortMappings, expectedMapping)
+	logContainerDiff(t, matched, expected, container)
+}
+
+// AssertMountPoint checks if an expected mount point exists in the container
+func AssertMountPoint(t testing.TB, container types.ContainerDefinition, expectedMount types.MountPoint) {
+	t.Helper()
+	assert.NotNil(t, expectedMount.SourceVolume, "Source volume cannot be nil")
+	assert.NotNil(t, expectedMount.ContainerPath, "Container path cannot be nil")
+	assert.NotNil(t, expectedMount.ReadOnly, "ReadOnly flag cannot be nil")
+
+	found := false
+	for _, mount := range container.MountPoints {
+		if mount.SourceVolume != nil && mount.ContainerPath != nil && mount.ReadOnly != nil &&
+			*mount.SourceVolume == aws.ToString(expectedMount.SourceVolume) &&
+			*mount.ContainerPath == aws.ToString(expectedMount.ContainerPath) &&
+			*mount.ReadOnly == aws.ToBool(expectedMount.ReadOnly) {
+			found = true
+			break
+		}
+	}
+	matched := assert.True(t, found, "Expected mount point (volume:%s, path:%s, readonly:%t) not found in %s container",
+		aws.ToString(expectedMount.SourceVolume), aws.ToString(expectedMount.ContainerPath), aws.ToBool(expectedMount.ReadOnly), aws.ToString(container.Name))
+
+	expected := container
+	expected.MountPoints = slices.DeleteFunc(slices.Clone(container.MountPoints), func(mount types.MountPoint) bool {
+		return aws.ToString(mount.SourceVolume) == aws.ToString(expectedMount.SourceVolume) &&
+			aws.ToString(mount.ContainerPath) == aws.ToString(expectedMount.ContainerPath)
+	})
+	expected.MountPoints = append(expected.MountPoints, expectedMount)
+	logContainerDiff(t, matched, expected, container)
+}
+
+// AssertContainerDependency checks if an expected container dependency exists
+func AssertContainerDependency(t testing.TB, container types.ContainerDefinition, expectedDependency types.ContainerDependency) {
+	t.Helper()
+	assert.NotNil(t, expectedDependency.ContainerName, "Dependency container name cannot be nil")
+
+	found := false
+	for _, dependency := range container.DependsOn {
+		if dependency.ContainerName != nil &&
+			*dependency.ContainerName == aws.ToString(expectedDependency.ContainerName) &&
+			dependency.Condition == expectedDependency.Condition {
+			found = true
+			break
+		}
+	}
+	matched := assert.True(t, found, "Expected dependency (container:%s, condition:%s) not found in %s container",
+		aws.ToString(expectedDependency.ContainerName), expectedDependency.Condition, aws.ToString(container.Name))
+
+	expected := container
+	expected.DependsOn = slices.DeleteFunc(slices.Clone(container.DependsOn), func(dependency types.ContainerDependency) bool {
+		return aws.ToString(dependency.ContainerName) == aws.ToString(expectedDependency.ContainerName)
+	})
+	expected.DependsOn = append(expected.DependsOn, expectedDependency)
+	logContainerDiff(t, matched, expected, container)
+}
+
+// AssertDockerLabels checks if the expected docker labels are all present in the container
+func AssertDockerLabels(t testing.TB, container types.ContainerDefinition, expectedLabels map[string]string) {
+	t.Helper()
+	assert.NotNil(t, container.Name, "Container name cannot be nil")
+
+	expected := container
+	expected.DockerLabels = maps.Clone(container.DockerLabels)
+	if expected.DockerLabels == nil {
+		expected.DockerLabels = map[string]string{}
+	}
+	matched := true
+	for key, expectedValue := range expectedLabels {
+		value, found := container.DockerLabels[key]
+		matched = assert.True(t, found, "Docker label %s not found in %s container", key, aws.ToString(container.Name)) && matched
+		matched = assert.Equal(t, expectedValue, value, "Docker label %s value does not match expected in %s container", key, aws.ToString(container.Name)) && matched
+		expected.DockerLabels[key] = expectedValue
+	}
+	logContainerDiff(t, matched, expected, container)
+}
+
+// logContainerDiff logs how a container differs from the expected one once an assertion on it failed,
+// so that every change to its definition shows up next to the failure
+func logContainerDiff(t testing.TB, matched bool, expected, actual types.ContainerDefinition) {
+	t.Helper()
+	if matched {
+		return
+	}
+	t.Logf("Container definition compared to the expected one:\n%s", DiffContainerDefinitions(
+		[]types.ContainerDefinition{expected}, []types.ContainerDefinition{actual}))
+}
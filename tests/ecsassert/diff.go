@@ -0,0 +1,274 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package ecsassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// ChangeKind tells whether an entry was added, removed or changed
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// FieldChange is a difference in one entry of a container field (eg. the environment variable DD_SITE)
+type FieldChange struct {
+	Field    string
+	Key      string
+	Kind     ChangeKind
+	Expected string
+	Actual   string
+}
+
+// ContainerDiff lists the differences of a container between two task definitions.
+// A container present on one side only has a Kind of added or removed and no changes.
+type ContainerDiff struct {
+	Name    string
+	Kind    ChangeKind
+	Changes []FieldChange
+}
+
+// ContainerDefinitionsDiff is the report returned by DiffContainerDefinitions
+type ContainerDefinitionsDiff []ContainerDiff
+
+// containerFields flattens the compared fields of a container to key-value entries, by field name
+var containerFields = []struct {
+	name    string
+	entries func(types.ContainerDefinition) map[string]string
+}{
+	{"environment", environmentEntries},
+	{"secrets", secretEntries},
+	{"mountPoints", mountPointEntries},
+	{"portMappings", portMappingEntries},
+	{"dependsOn", dependsOnEntries},
+	{"dockerLabels", func(c types.ContainerDefinition) map[string]string { return c.DockerLabels }},
+	{"logConfiguration", logConfigurationEntries},
+	{"linuxParameters", linuxParametersEntries},
+}
+
+// DiffContainerDefinitions reports per container the environment variables, secrets, mount points,
+// port mappings, dependencies, docker labels, log configuration options and linux parameters that
+// were added, removed or changed from expected to actual. Order differences are ignored.
+func DiffContainerDefinitions(expected, actual []types.ContainerDefinition) ContainerDefinitionsDiff {
+	expectedByName := containersByName(expected)
+	actualByName := containersByName(actual)
+
+	var diff ContainerDefinitionsDiff
+	for _, name := range unionKeys(expectedByName, actualByName) {
+		expectedContainer, inExpected := expectedByName[name]
+		actualContainer, inActual := actualByName[name]
+		switch {
+		case !inActual:
+			diff = append(diff, ContainerDiff{Name: name, Kind: ChangeRemoved})
+		case !inExpected:
+			diff = append(diff, ContainerDiff{Name: name, Kind: ChangeAdded})
+		default:
+			var changes []FieldChange
+			for _, field := range containerFields {
+				changes = append(changes, diffEntries(field.name, field.entries(expectedContainer), field.entries(actualContainer))...)
+			}
+			if len(changes) > 0 {
+				diff = append(diff, ContainerDiff{Name: name, Kind: ChangeChanged, Changes: changes})
+			}
+		}
+	}
+	return diff
+}
+
+// Empty reports whether the container definitions are equivalent
+func (d ContainerDefinitionsDiff) Empty() bool {
+	return len(d) == 0
+}
+
+// String formats the report, one line per container and per change
+func (d ContainerDefinitionsDiff) String() string {
+	if d.Empty() {
+		return "container definitions are equivalent"
+	}
+
+	var report strings.Builder
+	for _, container := range d {
+		if container.Kind != ChangeChanged {
+			fmt.Fprintf(&report, "container %s: %s\n", container.Name, container.Kind)
+			continue
+		}
+		fmt.Fprintf(&report, "container %s:\n", container.Name)
+		for _, change := range container.Changes {
+			switch change.Kind {
+			case ChangeAdded:
+				fmt.Fprintf(&report, "  %s %s: added %s\n", change.Field, change.Key, change.Actual)
+			case ChangeRemoved:
+				fmt.Fprintf(&report, "  %s %s: removed %s\n", change.Field, change.Key, change.Expected)
+			default:
+				fmt.Fprintf(&report, "  %s %s: changed %s -> %s\n", change.Field, change.Key, change.Expected, change.Actual)
+			}
+		}
+	}
+	return strings.TrimSuffix(report.String(), "\n")
+}
+
+// diffEntries compares the entries of a container field
+func diffEntries(field string, expected, actual map[string]string) []FieldChange {
+	var changes []FieldChange
+	for _, key := range unionKeys(expected, actual) {
+		expectedValue, inExpected := expected[key]
+		actualValue, inActual := actual[key]
+		switch {
+		case !inActual:
+			changes = append(changes, FieldChange{Field: field, Key: key, Kind: ChangeRemoved, Expected: expectedValue})
+		case !inExpected:
+			changes = append(changes, FieldChange{Field: field, Key: key, Kind: ChangeAdded, Actual: actualValue})
+		case expectedValue != actualValue:
+			changes = append(changes, FieldChange{Field: field, Key: key, Kind: ChangeChanged, Expected: expectedValue, Actual: actualValue})
+		}
+	}
+	return changes
+}
+
+func containersByName(containers []types.ContainerDefinition) map[string]types.ContainerDefinition {
+	byName := make(map[string]types.ContainerDefinition, len(containers))
+	for _, container := range containers {
+		byName[aws.ToString(container.Name)] = container
+	}
+	return byName
+}
+
+// unionKeys returns the sorted keys of two maps
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, found := a[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func quote(value *string) string {
+	if value == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%q", *value)
+}
+
+func environmentEntries(c types.ContainerDefinition) map[string]string {
+	entries := make(map[string]string, len(c.Environment))
+	for _, env := range c.Environment {
+		entries[aws.ToString(env.Name)] = quote(env.Value)
+	}
+	return entries
+}
+
+func secretEntries(c types.ContainerDefinition) map[string]string {
+	entries := make(map[string]string, len(c.Secrets))
+	for _, secret := range c.Secrets {
+		entries[aws.ToString(secret.Name)] = quote(secret.ValueFrom)
+	}
+	return entries
+}
+
+func mountPointEntries(c types.ContainerDefinition) map[string]string {
+	entries := make(map[string]string, len(c.MountPoints))
+	for _, mountPoint := range c.MountPoints {
+		entries[aws.ToString(mountPoint.SourceVolume)+":"+aws.ToString(mountPoint.ContainerPath)] = fmt.Sprintf("readOnly=%t", aws.ToBool(mountPoint.ReadOnly))
+	}
+	return entries
+}
+
+func portMappingEntries(c types.ContainerDefinition) map[string]string {
+	entries := make(map[string]string, len(c.PortMappings))
+	for _, mapping := range c.PortMappings {
+		protocol := mapping.Protocol
+		if protocol == "" {
+			protocol = types.TransportProtocolTcp
+		}
+		key := fmt.Sprintf("%d/%s", aws.ToInt32(mapping.ContainerPort), protocol)
+		entries[key] = fmt.Sprintf("hostPort=%d", aws.ToInt32(mapping.HostPort))
+	}
+	return entries
+}
+
+func dependsOnEntries(c types.ContainerDefinition) map[string]string {
+	entries := make(map[string]string, len(c.DependsOn))
+	for _, dependency := range c.DependsOn {
+		entries[aws.ToString(dependency.ContainerName)] = string(dependency.Condition)
+	}
+	return entries
+}
+
+func logConfigurationEntries(c types.ContainerDefinition) map[string]string {
+	entries := map[string]string{}
+	if c.LogConfiguration == nil {
+		return entries
+	}
+	entries["logDriver"] = string(c.LogConfiguration.LogDriver)
+	for key, value := range c.LogConfiguration.Options {
+		entries["options."+key] = fmt.Sprintf("%q", value)
+	}
+	for _, secret := range c.LogConfiguration.SecretOptions {
+		entries["secretOptions."+aws.ToString(secret.Name)] = quote(secret.ValueFrom)
+	}
+	return entries
+}
+
+// linuxParametersEntries flattens the linux parameters to paths, lists of values being compared as sets
+func linuxParametersEntries(c types.ContainerDefinition) map[string]string {
+	entries := map[string]string{}
+	if c.LinuxParameters == nil {
+		return entries
+	}
+	data, err := json.Marshal(c.LinuxParameters)
+	if err != nil {
+		return entries
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return entries
+	}
+	flattenJSON("", value, entries)
+	return entries
+}
+
+// flattenJSON writes an entry per scalar of value, keyed by its path. Unset and empty values have no entry.
+func flattenJSON(path string, value interface{}, entries map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			if element == nil || element == "" {
+				continue
+			}
+			flattenJSON(strings.TrimPrefix(path+"."+key, "."), element, entries)
+		}
+	case []interface{}:
+		for _, element := range v {
+			if _, isObject := element.(map[string]interface{}); isObject {
+				data, _ := json.Marshal(element)
+				flattenJSON(path+"["+string(data)+"]", nil, entries)
+			} else {
+				flattenJSON(fmt.Sprintf("%s[%v]", path, element), nil, entries)
+			}
+		}
+	case nil:
+		entries[path] = "present"
+	default:
+		data, _ := json.Marshal(v)
+		entries[path] = string(data)
+	}
+}
@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package ecsassert
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiffContainerDefinitions tests that container changes are reported per field, ignoring order
+func TestDiffContainerDefinitions(t *testing.T) {
+	expected := []types.ContainerDefinition{
+		{
+			Name: aws.String("datadog-agent"),
+			Environment: []types.KeyValuePair{
+				{Name: aws.String("DD_SITE"), Value: aws.String("datadoghq.com")},
+				{Name: aws.String("DD_ENV"), Value: aws.String("prod")},
+			},
+			MountPoints:  []types.MountPoint{MountDdSocket},
+			PortMappings: []types.PortMapping{PortUDP, PortTCP},
+			DockerLabels: map[string]string{"com.datadoghq.tags.env": "prod"},
+			LogConfiguration: &types.LogConfiguration{
+				LogDriver: types.LogDriverAwsfirelens,
+				Options:   map[string]string{"Name": "datadog", "dd_service": "agent"},
+			},
+			LinuxParameters: &types.LinuxParameters{
+				Capabilities: &types.KernelCapabilities{Add: []string{"SYS_ADMIN", "SYS_PTRACE"}},
+			},
+		},
+		{Name: aws.String("cws-instrumentation-init")},
+		{
+			Name:      aws.String("app"),
+			DependsOn: []types.ContainerDependency{DependencyAgent},
+		},
+	}
+	actual := []types.ContainerDefinition{
+		{
+			Name: aws.String("app"),
+			DependsOn: []types.ContainerDependency{
+				{ContainerName: aws.String("datadog-agent"), Condition: types.ContainerConditionStart},
+			},
+			Secrets: []types.Secret{{Name: aws.String("DD_API_KEY"), ValueFrom: aws.String("arn:secret")}},
+		},
+		{
+			Name: aws.String("datadog-agent"),
+			Environment: []types.KeyValuePair{
+				{Name: aws.String("DD_ENV"), Value: aws.String("prod")},
+				{Name: aws.String("DD_SITE"), Value: aws.String("datadoghq.eu")},
+			},
+			PortMappings: []types.PortMapping{PortTCP, PortUDP},
+			DockerLabels: map[string]string{"com.datadoghq.tags.env": "prod"},
+			LogConfiguration: &types.LogConfiguration{
+				LogDriver: types.LogDriverAwsfirelens,
+				Options:   map[string]string{"Name": "datadog", "dd_service": "agent-dev"},
+			},
+			LinuxParameters: &types.LinuxParameters{
+				Capabilities: &types.KernelCapabilities{Add: []string{"SYS_PTRACE"}},
+			},
+		},
+		{Name: aws.String("datadog-log-router")},
+	}
+
+	diff := DiffContainerDefinitions(expected, actual)
+	assert.Equal(t, ContainerDefinitionsDiff{
+		{Name: "app", Kind: ChangeChanged, Changes: []FieldChange{
+			{Field: "secrets", Key: "DD_API_KEY", Kind: ChangeAdded, Actual: `"arn:secret"`},
+			{Field: "dependsOn", Key: "datadog-agent", Kind: ChangeChanged, Expected: "HEALTHY", Actual: "START"},
+		}},
+		{Name: "cws-instrumentation-init", Kind: ChangeRemoved},
+		{Name: "datadog-agent", Kind: ChangeChanged, Changes: []FieldChange{
+			{Field: "environment", Key: "DD_SITE", Kind: ChangeChanged, Expected: `"datadoghq.com"`, Actual: `"datadoghq.eu"`},
+			{Field: "mountPoints", Key: "dd-sockets:/var/run/datadog", Kind: ChangeRemoved, Expected: "readOnly=false"},
+			{Field: "logConfiguration", Key: "options.dd_service", Kind: ChangeChanged, Expected: `"agent"`, Actual: `"agent-dev"`},
+			{Field: "linuxParameters", Key: "Capabilities.Add[SYS_ADMIN]", Kind: ChangeRemoved, Expected: "present"},
+		}},
+		{Name: "datadog-log-router", Kind: ChangeAdded},
+	}, diff)
+
+	assert.Equal(t, `container app:
+  secrets DD_API_KEY: added "arn:secret"
+  dependsOn datadog-agent: changed HEALTHY -> START
+container cws-instrumentation-init: removed
+container datadog-agent:
+  environment DD_SITE: changed "datadoghq.com" -> "datadoghq.eu"
+  mountPoints dd-sockets:/var/run/datadog: removed readOnly=false
+  logConfiguration options.dd_service: changed "agent" -> "agent-dev"
+  linuxParameters Capabilities.Add[SYS_ADMIN]: removed present
+container datadog-log-router: added`, diff.String())
+
+	assert.True(t, DiffContainerDefinitions(expected, expected).Empty())
+}
+
+// TestAssertionsLogContainerDiff tests that a failed assertion logs the container changes from what it expected
+func TestAssertionsLogContainerDiff(t *testing.T) {
+	agent := types.ContainerDefinition{
+		Name: aws.String("datadog-agent"),
+		Environment: []types.KeyValuePair{
+			{Name: aws.String("DD_SITE"), Value: aws.String("datadoghq.eu")},
+			{Name: aws.String("DD_LOGS_ENABLED"), Value: aws.String("true")},
+		},
+		MountPoints: []types.MountPoint{
+			{SourceVolume: aws.String("dd-sockets"), ContainerPath: aws.String("/var/run/datadog"), ReadOnly: aws.Bool(true)},
+		},
+	}
+
+	recorder := &recordingT{}
+	AssertEnvVars(recorder, agent, map[string]string{"DD_SITE": "datadoghq.com", "DD_API_KEY": "test-api-key"})
+	AssertNotEnvVars(recorder, agent, []string{"DD_LOGS_ENABLED"})
+	AssertMountPoint(recorder, agent, MountDdSocket)
+	assert.Equal(t, []string{
+		`Container definition compared to the expected one:
+container datadog-agent:
+  environment DD_API_KEY: removed "test-api-key"
+  environment DD_SITE: changed "datadoghq.com" -> "datadoghq.eu"`,
+		`Container definition compared to the expected one:
+container datadog-agent:
+  environment DD_LOGS_ENABLED: added "true"`,
+		`Container definition compared to the expected one:
+container datadog-agent:
+  mountPoints dd-sockets:/var/run/datadog: changed readOnly=false -> readOnly=true`,
+	}, recorder.logs)
+
+	passing := &recordingT{}
+	AssertEnvVars(passing, agent, map[string]string{"DD_SITE": "datadoghq.eu"})
+	AssertMountPoint(passing, agent, agent.MountPoints[0])
+	assert.Empty(t, passing.failures)
+	assert.Empty(t, passing.logs)
+}
@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package ecsassert asserts the container definitions rendered by the Datadog ECS modules.
+// It is the set of helpers the module's own smoke tests use, for the Terraform configurations
+// that wrap the modules to be tested the same way.
+//
+// The helpers take a testing.TB, so they work in plain tests, benchmarks and testify suites (`s.T()`):
+//
+//	containers := task.ContainerDefinitions
+//	agent, found := ecsassert.GetContainer(containers, "datadog-agent")
+//	require.True(t, found)
+//	ecsassert.AssertMountPoint(t, agent, ecsassert.MountDdSocket)
+//
+// Expect chains assertions on several containers and reports every mismatch in a single failure.
+// DiffContainerDefinitions reports the changes between two sets of containers, per container and
+// field; the Assert helpers log it when they fail.
+//
+// # Versioning
+//
+// The package is its own Go module, released with tags named `tests/ecsassert/vX.Y.Z` that follow
+// semantic versioning independently of the Terraform modules. A release that changes what the
+// modules render, such as a mount path, updates the fixtures in a minor version; removing or
+// renaming an exported identifier requires a major version.
+package ecsassert
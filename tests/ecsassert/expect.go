@@ -0,0 +1,522 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package ecsassert
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// Expectation collects the mismatches of chained assertions on the containers of a task definition.
+// Assertions never dereference a nil field of a definition, they record it as a mismatch instead.
+// All the mismatches are reported as a single failure by Check, or when the test ends if Check is not called.
+type Expectation struct {
+	t          testing.TB
+	containers []types.ContainerDefinition
+	mu         sync.Mutex
+	mismatches []string
+}
+
+// ContainerExpectation chains assertions on a single container. The assertions of a missing container are skipped.
+type ContainerExpectation struct {
+	expectation *Expectation
+	name        string
+	container   *types.ContainerDefinition
+}
+
+// Expect starts the assertions on the containers of a task definition:
+//
+//	Expect(t, containers).
+//		Container("datadog-agent").Essential().HasMount(MountDdSocket).DependsOn(DependencyLogRouter).
+//		Container("datadog-log-router").NotEssential().Firelens(types.FirelensConfigurationTypeFluentbit).
+//		Check()
+func Expect(t testing.TB, containers []types.ContainerDefinition) *Expectation {
+	e := &Expectation{t: t, containers: containers}
+	t.Cleanup(e.Check)
+	return e
+}
+
+// Check reports the mismatches recorded so far as a single failure. Later calls only report new mismatches.
+func (e *Expectation) Check() {
+	e.t.Helper()
+	e.mu.Lock()
+	mismatches := e.mismatches
+	e.mismatches = nil
+	e.mu.Unlock()
+
+	if len(mismatches) == 0 {
+		return
+	}
+	e.t.Errorf("%d container mismatches:\n  %s", len(mismatches), strings.Join(mismatches, "\n  "))
+}
+
+func (e *Expectation) mismatch(container string, format string, args ...interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.mismatches = append(e.mismatches, container+": "+fmt.Sprintf(format, args...))
+}
+
+// Container starts the assertions on a container, recording a mismatch if the task definition has none of this name
+func (e *Expectation) Container(name string) *ContainerExpectation {
+	c := &ContainerExpectation{expectation: e, name: name}
+	for i := range e.containers {
+		if aws.ToString(e.containers[i].Name) == name {
+			c.container = &e.containers[i]
+			return c
+		}
+	}
+	e.mismatch(name, "container not found, got %s", strings.Join(e.containerNames(), ", "))
+	return c
+}
+
+// NoContainer records a mismatch if the task definition has a container of this name
+func (e *Expectation) NoContainer(name string) *Expectation {
+	if slices.Contains(e.containerNames(), name) {
+		e.mismatch(name, "container should not be defined")
+	}
+	return e
+}
+
+// Containers records a mismatch unless the task definition has exactly these containers, in any order
+func (e *Expectation) Containers(names ...string) *Expectation {
+	actual := e.containerNames()
+	expected := slices.Clone(names)
+	sort.Strings(actual)
+	sort.Strings(expected)
+	if !slices.Equal(actual, expected) {
+		e.mismatch("task", "containers are %s, expected %s", strings.Join(actual, ", "), strings.Join(expected, ", "))
+	}
+	return e
+}
+
+func (e *Expectation) containerNames() []string {
+	names := make([]string, 0, len(e.containers))
+	for _, container := range e.containers {
+		names = append(names, aws.ToString(container.Name))
+	}
+	return names
+}
+
+// Container ends the assertions on this container and starts those on another one
+func (c *ContainerExpectation) Container(name string) *ContainerExpectation {
+	return c.expectation.Container(name)
+}
+
+// Check reports the mismatches of every container asserted so far
+func (c *ContainerExpectation) Check() {
+	c.expectation.t.Helper()
+	c.expectation.Check()
+}
+
+// Definition returns the container definition, nil if it was not found
+func (c *ContainerExpectation) Definition() *types.ContainerDefinition {
+	return c.container
+}
+
+// check records a mismatch when the container exists and the assertion returns a message
+func (c *ContainerExpectation) check(assertion func(container *types.ContainerDefinition) string) *ContainerExpectation {
+	if c.container == nil {
+		return c
+	}
+	if message := assertion(c.container); message != "" {
+		c.expectation.mismatch(c.name, "%s", message)
+	}
+	return c
+}
+
+// Image asserts the container image
+func (c *ContainerExpectation) Image(image string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		return compareString("image", container.Image, image)
+	})
+}
+
+// User asserts the user the container runs as
+func (c *ContainerExpectation) User(user string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		return compareString("user", container.User, user)
+	})
+}
+
+// Essential asserts that the container is essential, which is the ECS default when unset
+func (c *ContainerExpectation) Essential() *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if !aws.ToBool(container.Essential) && container.Essential != nil {
+			return "should be essential"
+		}
+		return ""
+	})
+}
+
+// NotEssential asserts that the container is explicitly not essential
+func (c *ContainerExpectation) NotEssential() *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.Essential == nil || *container.Essential {
+			return "should not be essential"
+		}
+		return ""
+	})
+}
+
+// ReadonlyRootFilesystem asserts that the root filesystem of the container is read-only
+func (c *ContainerExpectation) ReadonlyRootFilesystem() *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if !aws.ToBool(container.ReadonlyRootFilesystem) {
+			return "root filesystem should be read-only"
+		}
+		return ""
+	})
+}
+
+// WritableRootFilesystem asserts that the root filesystem of the container is explicitly writable
+func (c *ContainerExpectation) WritableRootFilesystem() *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.ReadonlyRootFilesystem == nil || *container.ReadonlyRootFilesystem {
+			return "root filesystem should be writable"
+		}
+		return ""
+	})
+}
+
+// Cpu asserts the CPU units reserved for the container
+func (c *ContainerExpectation) Cpu(cpu int32) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.Cpu != cpu {
+			return fmt.Sprintf("cpu is %d, expected %d", container.Cpu, cpu)
+		}
+		return ""
+	})
+}
+
+// Memory asserts the hard memory limit of the container, in MiB
+func (c *ContainerExpectation) Memory(memory int32) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.Memory == nil {
+			return fmt.Sprintf("memory is not set, expected %d", memory)
+		}
+		if *container.Memory != memory {
+			return fmt.Sprintf("memory is %d, expected %d", *container.Memory, memory)
+		}
+		return ""
+	})
+}
+
+// Command asserts the command of the container
+func (c *ContainerExpectation) Command(command ...string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		return compareStrings("command", container.Command, command)
+	})
+}
+
+// EntryPoint asserts the entry point of the container
+func (c *ContainerExpectation) EntryPoint(entryPoint ...string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		return compareStrings("entry point", container.EntryPoint, entryPoint)
+	})
+}
+
+// HasEnv asserts that the container has the environment variables, with these values
+func (c *ContainerExpectation) HasEnv(env map[string]string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		var messages []string
+		for _, name := range slices.Sorted(maps.Keys(env)) {
+			value, found := GetEnvVar(*container, name)
+			switch {
+			case !found:
+				messages = append(messages, fmt.Sprintf("missing env %s=%q", name, env[name]))
+			case value != env[name]:
+				messages = append(messages, fmt.Sprintf("env %s is %q, expected %q", name, value, env[name]))
+			}
+		}
+		return strings.Join(messages, "; ")
+	})
+}
+
+// LacksEnv asserts that the container has none of the environment variables
+func (c *ContainerExpectation) LacksEnv(names ...string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		var unexpected []string
+		for _, name := range names {
+			if _, found := GetEnvVar(*container, name); found {
+				unexpected = append(unexpected, name)
+			}
+		}
+		if len(unexpected) > 0 {
+			return "unexpected env " + strings.Join(unexpected, ", ")
+		}
+		return ""
+	})
+}
+
+// HasSecret asserts that the container has the secret environment variable, read from valueFrom
+func (c *ContainerExpectation) HasSecret(name string, valueFrom string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		for _, secret := range container.Secrets {
+			if aws.ToString(secret.Name) == name {
+				return compareString("secret "+name, secret.ValueFrom, valueFrom)
+			}
+		}
+		return fmt.Sprintf("missing secret %s from %s", name, valueFrom)
+	})
+}
+
+// HasPort asserts that the container has the port mappings
+func (c *ContainerExpectation) HasPort(mappings ...types.PortMapping) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		var messages []string
+		for _, expected := range mappings {
+			if !slices.ContainsFunc(container.PortMappings, func(mapping types.PortMapping) bool {
+				return aws.ToInt32(mapping.ContainerPort) == aws.ToInt32(expected.ContainerPort) &&
+					aws.ToInt32(mapping.HostPort) == aws.ToInt32(expected.HostPort) &&
+					mapping.Protocol == expected.Protocol
+			}) {
+				messages = append(messages, fmt.Sprintf("missing port mapping %d:%d/%s",
+					aws.ToInt32(expected.ContainerPort), aws.ToInt32(expected.HostPort), expected.Protocol))
+			}
+		}
+		return strings.Join(messages, "; ")
+	})
+}
+
+// HasMount asserts that the container has the mount points
+func (c *ContainerExpectation) HasMount(mounts ...types.MountPoint) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		var messages []string
+		for _, expected := range mounts {
+			if !slices.ContainsFunc(container.MountPoints, func(mount types.MountPoint) bool {
+				return aws.ToString(mount.SourceVolume) == aws.ToString(expected.SourceVolume) &&
+					aws.ToString(mount.ContainerPath) == aws.ToString(expected.ContainerPath) &&
+					aws.ToBool(mount.ReadOnly) == aws.ToBool(expected.ReadOnly)
+			}) {
+				messages = append(messages, fmt.Sprintf("missing mount point %s:%s (read-only: %t)",
+					aws.ToString(expected.SourceVolume), aws.ToString(expected.ContainerPath), aws.ToBool(expected.ReadOnly)))
+			}
+		}
+		return strings.Join(messages, "; ")
+	})
+}
+
+// NoMounts asserts that the container has no mount points
+func (c *ContainerExpectation) NoMounts() *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if len(container.MountPoints) > 0 {
+			return fmt.Sprintf("has %d mount points, expected none", len(container.MountPoints))
+		}
+		return ""
+	})
+}
+
+// LacksMount asserts that the container does not mount the volume
+func (c *ContainerExpectation) LacksMount(sourceVolume string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if slices.ContainsFunc(container.MountPoints, func(mount types.MountPoint) bool {
+			return aws.ToString(mount.SourceVolume) == sourceVolume
+		}) {
+			return "unexpected mount of volume " + sourceVolume
+		}
+		return ""
+	})
+}
+
+// DependsOn asserts that the container depends on the other containers with these conditions
+func (c *ContainerExpectation) DependsOn(dependencies ...types.ContainerDependency) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		var messages []string
+		for _, expected := range dependencies {
+			if !slices.ContainsFunc(container.DependsOn, func(dependency types.ContainerDependency) bool {
+				return aws.ToString(dependency.ContainerName) == aws.ToString(expected.ContainerName) && dependency.Condition == expected.Condition
+			}) {
+				messages = append(messages, fmt.Sprintf("missing dependency on %s (%s)", aws.ToString(expected.ContainerName), expected.Condition))
+			}
+		}
+		return strings.Join(messages, "; ")
+	})
+}
+
+// OnlyDependsOn asserts that the container depends on exactly these containers, with these conditions
+func (c *ContainerExpectation) OnlyDependsOn(dependencies ...types.ContainerDependency) *ContainerExpectation {
+	c.DependsOn(dependencies...)
+	return c.check(func(container *types.ContainerDefinition) string {
+		if len(container.DependsOn) != len(dependencies) {
+			return fmt.Sprintf("has %d dependencies, expected %d", len(container.DependsOn), len(dependencies))
+		}
+		return ""
+	})
+}
+
+// HasDockerLabels asserts that the container has the docker labels, with these values
+func (c *ContainerExpectation) HasDockerLabels(labels map[string]string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		return compareMap("docker label", container.DockerLabels, labels)
+	})
+}
+
+// LogDriver asserts the log driver of the container
+func (c *ContainerExpectation) LogDriver(driver types.LogDriver) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.LogConfiguration == nil {
+			return fmt.Sprintf("log configuration is not set, expected driver %s", driver)
+		}
+		if container.LogConfiguration.LogDriver != driver {
+			return fmt.Sprintf("log driver is %s, expected %s", container.LogConfiguration.LogDriver, driver)
+		}
+		return ""
+	})
+}
+
+// HasLogOption asserts an option of the log configuration of the container
+func (c *ContainerExpectation) HasLogOption(key string, value string) *ContainerExpectation {
+	return c.HasLogOptions(map[string]string{key: value})
+}
+
+// HasLogOptions asserts options of the log configuration of the container
+func (c *ContainerExpectation) HasLogOptions(options map[string]string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.LogConfiguration == nil {
+			return "log configuration is not set, expected options " + strings.Join(slices.Sorted(maps.Keys(options)), ", ")
+		}
+		return compareMap("log option", container.LogConfiguration.Options, options)
+	})
+}
+
+// HealthCheckCommand asserts the command of the health check of the container
+func (c *ContainerExpectation) HealthCheckCommand(command ...string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.HealthCheck == nil {
+			return "health check is not set"
+		}
+		return compareStrings("health check command", container.HealthCheck.Command, command)
+	})
+}
+
+// HealthCheckTiming asserts the interval, timeout, retries and start period of the health check of the container
+func (c *ContainerExpectation) HealthCheckTiming(interval, timeout, retries, startPeriod int32) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.HealthCheck == nil {
+			return "health check is not set"
+		}
+		var messages []string
+		for _, field := range []struct {
+			name     string
+			actual   *int32
+			expected int32
+		}{
+			{"interval", container.HealthCheck.Interval, interval},
+			{"timeout", container.HealthCheck.Timeout, timeout},
+			{"retries", container.HealthCheck.Retries, retries},
+			{"start period", container.HealthCheck.StartPeriod, startPeriod},
+		} {
+			switch {
+			case field.actual == nil:
+				messages = append(messages, fmt.Sprintf("health check %s is not set, expected %d", field.name, field.expected))
+			case *field.actual != field.expected:
+				messages = append(messages, fmt.Sprintf("health check %s is %d, expected %d", field.name, *field.actual, field.expected))
+			}
+		}
+		return strings.Join(messages, "; ")
+	})
+}
+
+// NoHealthCheck asserts that the container has no health check
+func (c *ContainerExpectation) NoHealthCheck() *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.HealthCheck != nil {
+			return fmt.Sprintf("unexpected health check %q", container.HealthCheck.Command)
+		}
+		return ""
+	})
+}
+
+// Firelens asserts that the container is a FireLens log router of this type
+func (c *ContainerExpectation) Firelens(firelensType types.FirelensConfigurationType) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.FirelensConfiguration == nil {
+			return fmt.Sprintf("firelens configuration is not set, expected type %s", firelensType)
+		}
+		if container.FirelensConfiguration.Type != firelensType {
+			return fmt.Sprintf("firelens type is %s, expected %s", container.FirelensConfiguration.Type, firelensType)
+		}
+		return ""
+	})
+}
+
+// HasFirelensOption asserts an option of the FireLens configuration of the container
+func (c *ContainerExpectation) HasFirelensOption(key string, value string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.FirelensConfiguration == nil {
+			return fmt.Sprintf("firelens configuration is not set, expected option %s=%q", key, value)
+		}
+		return compareMap("firelens option", container.FirelensConfiguration.Options, map[string]string{key: value})
+	})
+}
+
+// AddsCapabilities asserts that the linux parameters of the container add the kernel capabilities
+func (c *ContainerExpectation) AddsCapabilities(capabilities ...string) *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.LinuxParameters == nil || container.LinuxParameters.Capabilities == nil {
+			return "linux capabilities are not set, expected to add " + strings.Join(capabilities, ", ")
+		}
+		var missing []string
+		for _, capability := range capabilities {
+			if !slices.Contains(container.LinuxParameters.Capabilities.Add, capability) {
+				missing = append(missing, capability)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Sprintf("linux capabilities add %q, expected %s", container.LinuxParameters.Capabilities.Add, strings.Join(missing, ", "))
+		}
+		return ""
+	})
+}
+
+// NoLinuxParameters asserts that the container has no linux parameters
+func (c *ContainerExpectation) NoLinuxParameters() *ContainerExpectation {
+	return c.check(func(container *types.ContainerDefinition) string {
+		if container.LinuxParameters != nil {
+			return "linux parameters should not be set"
+		}
+		return ""
+	})
+}
+
+func compareString(field string, actual *string, expected string) string {
+	switch {
+	case actual == nil:
+		return fmt.Sprintf("%s is not set, expected %q", field, expected)
+	case *actual != expected:
+		return fmt.Sprintf("%s is %q, expected %q", field, *actual, expected)
+	}
+	return ""
+}
+
+func compareStrings(field string, actual []string, expected []string) string {
+	if !slices.Equal(actual, expected) {
+		return fmt.Sprintf("%s is %q, expected %q", field, actual, expected)
+	}
+	return ""
+}
+
+func compareMap(field string, actual map[string]string, expected map[string]string) string {
+	var messages []string
+	for _, key := range slices.Sorted(maps.Keys(expected)) {
+		value, found := actual[key]
+		switch {
+		case !found:
+			messages = append(messages, fmt.Sprintf("missing %s %s=%q", field, key, expected[key]))
+		case value != expected[key]:
+			messages = append(messages, fmt.Sprintf("%s %s is %q, expected %q", field, key, value, expected[key]))
+		}
+	}
+	return strings.Join(messages, "; ")
+}
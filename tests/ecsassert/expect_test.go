@@ -0,0 +1,157 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package ecsassert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingT records the failures, logs and cleanups of an expectation instead of failing the test
+type recordingT struct {
+	testing.TB
+	failures []string
+	logs     []string
+	cleanups []func()
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Name() string { return "recording" }
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Logf(format string, args ...interface{}) {
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+// TestExpectAggregatesMismatches tests that every mismatch is reported in a single failure, without dereferencing unset fields
+func TestExpectAggregatesMismatches(t *testing.T) {
+	containers := []types.ContainerDefinition{
+		{
+			Name:        aws.String("datadog-agent"),
+			Image:       aws.String("public.ecr.aws/datadog/agent:latest"),
+			Essential:   aws.Bool(true),
+			Environment: []types.KeyValuePair{{Name: aws.String("DD_SITE"), Value: aws.String("datadoghq.eu")}},
+			MountPoints: []types.MountPoint{MountDdSocket},
+			DependsOn:   []types.ContainerDependency{DependencyLogRouter},
+			LogConfiguration: &types.LogConfiguration{
+				LogDriver: types.LogDriverAwsfirelens,
+				Options:   map[string]string{"TLS": "on"},
+			},
+			Secrets: []types.Secret{{Name: aws.String("DD_API_KEY"), ValueFrom: aws.String("arn:aws:secretsmanager:us-east-1:123456789012:secret:dd-api-key")}},
+		},
+		// Every optional field of the log router is unset
+		{Name: aws.String("datadog-log-router")},
+	}
+
+	recorder := &recordingT{}
+	Expect(recorder, containers).
+		Containers("datadog-agent").
+		Container("datadog-agent").
+		Image("public.ecr.aws/datadog/agent:latest").
+		Essential().
+		HasEnv(map[string]string{"DD_SITE": "datadoghq.com", "DD_API_KEY": "test-api-key"}).
+		HasMount(MountDdSocket).
+		DependsOn(DependencyLogRouter).
+		HasLogOption("TLS", "on").
+		HasSecret("DD_API_KEY", "arn:aws:secretsmanager:us-east-1:123456789012:secret:dd-api-key").
+		Container("datadog-log-router").
+		NotEssential().
+		User("0").
+		Memory(128).
+		LogDriver(types.LogDriverAwsfirelens).
+		Firelens(types.FirelensConfigurationTypeFluentbit).
+		HasFirelensOption("enable-ecs-log-metadata", "true").
+		HealthCheckCommand("CMD-SHELL", "exit 0").
+		AddsCapabilities("SYS_PTRACE").
+		Container("datadog-cws-app").
+		User("0")
+	assert.Empty(t, recorder.failures, "Mismatches should only be reported on Check")
+
+	require.Len(t, recorder.cleanups, 1)
+	recorder.cleanups[0]()
+	require.Len(t, recorder.failures, 1)
+	assert.Equal(t, `11 container mismatches:
+  task: containers are datadog-agent, datadog-log-router, expected datadog-agent
+  datadog-agent: missing env DD_API_KEY="test-api-key"; env DD_SITE is "datadoghq.eu", expected "datadoghq.com"
+  datadog-log-router: should not be essential
+  datadog-log-router: user is not set, expected "0"
+  datadog-log-router: memory is not set, expected 128
+  datadog-log-router: log configuration is not set, expected driver awsfirelens
+  datadog-log-router: firelens configuration is not set, expected type fluentbit
+  datadog-log-router: firelens configuration is not set, expected option enable-ecs-log-metadata="true"
+  datadog-log-router: health check is not set
+  datadog-log-router: linux capabilities are not set, expected to add SYS_PTRACE
+  datadog-cws-app: container not found, got datadog-agent, datadog-log-router`, recorder.failures[0])
+}
+
+// TestExpectCheck tests that Check reports the mismatches once, and nothing when every assertion holds
+func TestExpectCheck(t *testing.T) {
+	containers := []types.ContainerDefinition{{
+		Name:      aws.String("datadog-log-router"),
+		Essential: aws.Bool(false),
+		FirelensConfiguration: &types.FirelensConfiguration{
+			Type:    types.FirelensConfigurationTypeFluentbit,
+			Options: map[string]string{"enable-ecs-log-metadata": "true"},
+		},
+	}}
+
+	recorder := &recordingT{}
+	expectation := Expect(recorder, containers)
+	expectation.Container("datadog-log-router").NotEssential().Firelens(types.FirelensConfigurationTypeFluentbit).NoLinuxParameters().Check()
+	assert.Empty(t, recorder.failures)
+
+	expectation.NoContainer("datadog-log-router").Check()
+	expectation.Check()
+	recorder.cleanups[0]()
+	assert.Equal(t, []string{"1 container mismatches:\n  datadog-log-router: container should not be defined"}, recorder.failures)
+}
+
+// TestExpectContainerShape tests the assertions on the mount points, dependencies, health check and root filesystem
+func TestExpectContainerShape(t *testing.T) {
+	containers := []types.ContainerDefinition{
+		{
+			Name:        aws.String("datadog-agent"),
+			MountPoints: []types.MountPoint{MountDdSocket},
+			DependsOn:   []types.ContainerDependency{DependencyLogRouter, DependencyCWS},
+			HealthCheck: &types.HealthCheck{Command: []string{"CMD-SHELL", "/probe.sh"}, Interval: aws.Int32(15), Timeout: aws.Int32(5)},
+		},
+		{Name: aws.String("datadog-log-router"), ReadonlyRootFilesystem: aws.Bool(false)},
+	}
+
+	recorder := &recordingT{}
+	Expect(recorder, containers).
+		Container("datadog-agent").
+		NoMounts().
+		LacksMount("dd-sockets").
+		OnlyDependsOn(DependencyLogRouter).
+		HealthCheckTiming(15, 5, 3, 60).
+		WritableRootFilesystem().
+		Container("datadog-log-router").
+		NoMounts().
+		LacksMount("dd-sockets").
+		WritableRootFilesystem().
+		NoHealthCheck().
+		Check()
+	assert.Equal(t, []string{`5 container mismatches:
+  datadog-agent: has 1 mount points, expected none
+  datadog-agent: unexpected mount of volume dd-sockets
+  datadog-agent: has 2 dependencies, expected 1
+  datadog-agent: health check retries is not set, expected 3; health check start period is not set, expected 60
+  datadog-agent: root filesystem should be writable`}, recorder.failures)
+}
@@ -0,0 +1,31 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package ecsassert
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// Mount points, port mappings and container dependencies the modules set on the containers of a task definition
+var (
+	MountDdSocket       = types.MountPoint{SourceVolume: aws.String("dd-sockets"), ContainerPath: aws.String("/var/run/datadog"), ReadOnly: aws.Bool(false)}
+	MountCWS            = types.MountPoint{SourceVolume: aws.String("cws-instrumentation-volume"), ContainerPath: aws.String("/cws-instrumentation-volume"), ReadOnly: aws.Bool(false)}
+	MountInitVolume     = types.MountPoint{SourceVolume: aws.String("agent-config"), ContainerPath: aws.String("/agent-config"), ReadOnly: aws.Bool(false)}
+	MountAgentConfig    = types.MountPoint{SourceVolume: aws.String("agent-config"), ContainerPath: aws.String("/etc/datadog-agent"), ReadOnly: aws.Bool(false)}
+	MountAgentTmp       = types.MountPoint{SourceVolume: aws.String("agent-tmp"), ContainerPath: aws.String("/tmp"), ReadOnly: aws.Bool(false)}
+	MountAgentRun       = types.MountPoint{SourceVolume: aws.String("agent-run"), ContainerPath: aws.String("/opt/datadog-agent/run"), ReadOnly: aws.Bool(false)}
+	MountDockerSock     = types.MountPoint{SourceVolume: aws.String("docker_sock"), ContainerPath: aws.String("/var/run/docker.sock"), ReadOnly: aws.Bool(true)}
+	MountProc           = types.MountPoint{SourceVolume: aws.String("proc"), ContainerPath: aws.String("/host/proc"), ReadOnly: aws.Bool(true)}
+	MountCgroup         = types.MountPoint{SourceVolume: aws.String("cgroup"), ContainerPath: aws.String("/host/sys/fs/cgroup"), ReadOnly: aws.Bool(true)}
+	MountPointdir       = types.MountPoint{SourceVolume: aws.String("pointdir"), ContainerPath: aws.String("/opt/datadog-agent/run"), ReadOnly: aws.Bool(false)}
+	MountContainersRoot = types.MountPoint{SourceVolume: aws.String("containers_root"), ContainerPath: aws.String("/var/lib/docker/containers"), ReadOnly: aws.Bool(true)}
+	PortTCP             = types.PortMapping{ContainerPort: aws.Int32(8126), HostPort: aws.Int32(8126), Protocol: types.TransportProtocolTcp}
+	PortUDP             = types.PortMapping{ContainerPort: aws.Int32(8125), HostPort: aws.Int32(8125), Protocol: types.TransportProtocolUdp}
+	DependencyAgent     = types.ContainerDependency{ContainerName: aws.String("datadog-agent"), Condition: types.ContainerConditionHealthy}
+	DependencyCWS       = types.ContainerDependency{ContainerName: aws.String("cws-instrumentation-init"), Condition: types.ContainerConditionSuccess}
+	DependencyLogRouter = types.ContainerDependency{ContainerName: aws.String("datadog-log-router"), Condition: types.ContainerConditionHealthy}
+)
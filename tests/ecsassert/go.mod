@@ -0,0 +1,16 @@
+module github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert
+
+go 1.25.7
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.36.3
+	github.com/aws/aws-sdk-go-v2/service/ecs v1.56.3
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/aws/smithy-go v1.22.2 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
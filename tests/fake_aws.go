@@ -0,0 +1,34 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"net/http/httptest"
+	"os"
+	"strconv"
+
+	"github.com/DataDog/terraform-ecs-datadog/tests/fakeaws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// FakeAWSEnvVar runs the suites against the in-repo AWS stand-in when set to a true value
+// (eg. `TERRAFORM_FAKE_AWS=true`). Terraform applies and destroys the smoke tests as usual,
+// but every AWS API call is served locally by package fakeaws.
+const FakeAWSEnvVar = "TERRAFORM_FAKE_AWS"
+
+// IsFakeAWS reports whether the suites should run against the local AWS stand-in
+func IsFakeAWS() bool {
+	fakeAWS, err := strconv.ParseBool(os.Getenv(FakeAWSEnvVar))
+	return err == nil && fakeAWS
+}
+
+// StartFakeAWS starts the local AWS stand-in and points the AWS provider's custom endpoints at it.
+// The caller closes the server once the smoke tests are destroyed.
+func StartFakeAWS(options *terraform.Options) *httptest.Server {
+	server := httptest.NewServer(fakeaws.New())
+	options.Vars["aws_endpoint_url"] = server.URL
+	return server
+}
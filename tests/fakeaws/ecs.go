@@ -0,0 +1,459 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package fakeaws
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ecsTargetPrefix = "AmazonEC2ContainerServiceV20141113."
+	amzJSON11       = "application/x-amz-json-1.1"
+)
+
+// taskDefinition is a registered task definition revision. The definition is kept as
+// sent by the client so that the provider reads back exactly what the module rendered.
+type taskDefinition struct {
+	arn        string
+	family     string
+	revision   int
+	status     string
+	definition map[string]interface{}
+}
+
+// service is an ECS service, kept as the API describes it
+type service struct {
+	arn         string
+	description map[string]interface{}
+}
+
+type ecsHandler func(s *Server, r *http.Request, input map[string]interface{}) (interface{}, *awsError)
+
+var ecsHandlers = map[string]ecsHandler{
+	"RegisterTaskDefinition":   (*Server).registerTaskDefinition,
+	"DescribeTaskDefinition":   (*Server).describeTaskDefinition,
+	"DeregisterTaskDefinition": (*Server).deregisterTaskDefinition,
+	"DeleteTaskDefinitions":    (*Server).deleteTaskDefinitions,
+	"ListTaskDefinitions":      (*Server).listTaskDefinitions,
+	"CreateService":            (*Server).createService,
+	"DescribeServices":         (*Server).describeServices,
+	"UpdateService":            (*Server).updateService,
+	"DeleteService":            (*Server).deleteService,
+	"ListServices":             (*Server).listServices,
+	"ListClusters":             (*Server).listClusters,
+	"ListTagsForResource":      (*Server).listECSTags,
+	"TagResource":              (*Server).tagECSResource,
+	"UntagResource":            (*Server).untagECSResource,
+}
+
+func (s *Server) serveECS(w http.ResponseWriter, r *http.Request, operation string) {
+	handler, found := ecsHandlers[operation]
+	if !found {
+		writeJSONError(w, newError(http.StatusBadRequest, "UnknownOperationException", "ECS operation %s is not supported", operation))
+		return
+	}
+
+	input := map[string]interface{}{}
+	if err := decodeJSON(r, &input); err != nil {
+		writeJSONError(w, err)
+		return
+	}
+
+	output, err := handler(s, r, input)
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	writeJSON(w, amzJSON11, http.StatusOK, output)
+}
+
+// writeJSONError writes an error of the JSON 1.1 protocol
+func writeJSONError(w http.ResponseWriter, err *awsError) {
+	writeJSON(w, amzJSON11, err.status, map[string]string{
+		"__type":  err.code,
+		"message": err.message,
+	})
+}
+
+func clientException(format string, args ...interface{}) *awsError {
+	return newError(http.StatusBadRequest, "ClientException", format, args...)
+}
+
+func (s *Server) registerTaskDefinition(r *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	family := stringValue(input, "family")
+	if family == "" {
+		return nil, clientException("Family must not be empty.")
+	}
+	if _, ok := input["containerDefinitions"].([]interface{}); !ok {
+		return nil, clientException("Container.image should not be null or empty.")
+	}
+
+	revision := len(s.taskDefinitions[family]) + 1
+	arn := fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/%s:%d", requestRegion(r), AccountID, family, revision)
+
+	definition := map[string]interface{}{}
+	for k, v := range input {
+		if k != "tags" {
+			definition[k] = v
+		}
+	}
+	definition["taskDefinitionArn"] = arn
+	definition["revision"] = revision
+	definition["status"] = "ACTIVE"
+	definition["registeredAt"] = epochSeconds(time.Now())
+	definition["registeredBy"] = fmt.Sprintf("arn:aws:iam::%s:root", AccountID)
+	definition["requiresAttributes"] = []interface{}{}
+	definition["compatibilities"] = compatibilities(input)
+
+	s.taskDefinitions[family] = append(s.taskDefinitions[family], &taskDefinition{
+		arn:        arn,
+		family:     family,
+		revision:   revision,
+		status:     "ACTIVE",
+		definition: definition,
+	})
+	s.ecsTags[arn] = tagsFromList(input["tags"], "key", "value")
+
+	return map[string]interface{}{
+		"taskDefinition": definition,
+		"tags":           tagsToList(s.ecsTags[arn], "key", "value"),
+	}, nil
+}
+
+func (s *Server) describeTaskDefinition(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	td := s.findTaskDefinition(stringValue(input, "taskDefinition"))
+	if td == nil {
+		return nil, clientException("Unable to describe task definition.")
+	}
+
+	output := map[string]interface{}{"taskDefinition": td.definition}
+	if slices.Contains(stringsValue(input, "include"), "TAGS") {
+		output["tags"] = tagsToList(s.ecsTags[td.arn], "key", "value")
+	}
+	return output, nil
+}
+
+func (s *Server) deregisterTaskDefinition(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	td := s.findTaskDefinition(stringValue(input, "taskDefinition"))
+	if td == nil {
+		return nil, clientException("The specified task definition does not exist.")
+	}
+
+	td.status = "INACTIVE"
+	td.definition["status"] = "INACTIVE"
+	td.definition["deregisteredAt"] = epochSeconds(time.Now())
+	return map[string]interface{}{"taskDefinition": td.definition}, nil
+}
+
+func (s *Server) deleteTaskDefinitions(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	deleted := []interface{}{}
+	failures := []interface{}{}
+	for _, name := range stringsValue(input, "taskDefinitions") {
+		td := s.findTaskDefinition(name)
+		switch {
+		case td == nil:
+			failures = append(failures, map[string]interface{}{"arn": name, "reason": "TASK_DEFINITION_NOT_FOUND"})
+		case td.status != "INACTIVE":
+			failures = append(failures, map[string]interface{}{"arn": td.arn, "reason": "The specified task definition is still in ACTIVE status."})
+		default:
+			td.status = "DELETE_IN_PROGRESS"
+			td.definition["status"] = "DELETE_IN_PROGRESS"
+			delete(s.ecsTags, td.arn)
+			deleted = append(deleted, td.definition)
+		}
+	}
+	return map[string]interface{}{"taskDefinitions": deleted, "failures": failures}, nil
+}
+
+func (s *Server) listTaskDefinitions(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	status := stringValue(input, "status")
+	if status == "" {
+		status = "ACTIVE"
+	}
+	prefix := stringValue(input, "familyPrefix")
+
+	families := make([]string, 0, len(s.taskDefinitions))
+	for family := range s.taskDefinitions {
+		if strings.HasPrefix(family, prefix) {
+			families = append(families, family)
+		}
+	}
+	sort.Strings(families)
+
+	arns := []string{}
+	for _, family := range families {
+		for _, td := range s.taskDefinitions[family] {
+			if td.status == status {
+				arns = append(arns, td.arn)
+			}
+		}
+	}
+	return map[string]interface{}{"taskDefinitionArns": arns}, nil
+}
+
+// findTaskDefinition resolves a task definition from its ARN, `family:revision` or family (latest ACTIVE revision)
+func (s *Server) findTaskDefinition(name string) *taskDefinition {
+	if strings.HasPrefix(name, "arn:") {
+		i := strings.Index(name, ":task-definition/")
+		if i < 0 {
+			return nil
+		}
+		name = name[i+len(":task-definition/"):]
+	}
+
+	family, rev, hasRevision := strings.Cut(name, ":")
+	revisions := s.taskDefinitions[family]
+	if !hasRevision {
+		for i := len(revisions) - 1; i >= 0; i-- {
+			if revisions[i].status == "ACTIVE" {
+				return revisions[i]
+			}
+		}
+		return nil
+	}
+
+	revision, err := strconv.Atoi(rev)
+	if err != nil || revision < 1 || revision > len(revisions) || revisions[revision-1].status == "DELETE_IN_PROGRESS" {
+		return nil
+	}
+	return revisions[revision-1]
+}
+
+// compatibilities returns the launch types a task definition is compatible with
+func compatibilities(input map[string]interface{}) []string {
+	result := []string{"EC2"}
+	if slices.Contains(stringsValue(input, "requiresCompatibilities"), "FARGATE") || stringValue(input, "networkMode") == "awsvpc" {
+		result = append(result, "FARGATE")
+	}
+	return result
+}
+
+func (s *Server) createService(r *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	name := stringValue(input, "serviceName")
+	if name == "" {
+		return nil, newError(http.StatusBadRequest, "InvalidParameterException", "Service name must not be empty.")
+	}
+	clusterArn := s.clusterArn(r, stringValue(input, "cluster"))
+	arn := fmt.Sprintf("arn:aws:ecs:%s:%s:service/%s/%s", requestRegion(r), AccountID, clusterName(clusterArn), name)
+
+	if existing, found := s.services[arn]; found && existing.description["status"] == "ACTIVE" {
+		return nil, newError(http.StatusBadRequest, "InvalidParameterException", "Creation of service was not idempotent.")
+	}
+
+	td := s.findTaskDefinition(stringValue(input, "taskDefinition"))
+	if td == nil {
+		return nil, clientException("TaskDefinition not found.")
+	}
+
+	description := map[string]interface{}{}
+	for k, v := range input {
+		if k != "tags" && k != "cluster" && k != "clientToken" {
+			description[k] = v
+		}
+	}
+	description["serviceArn"] = arn
+	description["clusterArn"] = clusterArn
+	description["taskDefinition"] = td.arn
+	description["status"] = "ACTIVE"
+	description["runningCount"] = 0
+	description["pendingCount"] = 0
+	description["createdAt"] = epochSeconds(time.Now())
+	if _, found := description["schedulingStrategy"]; !found {
+		description["schedulingStrategy"] = "REPLICA"
+	}
+	if _, found := description["desiredCount"]; !found {
+		description["desiredCount"] = 0
+	}
+
+	s.services[arn] = &service{arn: arn, description: description}
+	s.ecsTags[arn] = tagsFromList(input["tags"], "key", "value")
+	return map[string]interface{}{"service": description}, nil
+}
+
+func (s *Server) describeServices(r *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	clusterArn := s.clusterArn(r, stringValue(input, "cluster"))
+	services := []interface{}{}
+	failures := []interface{}{}
+	for _, name := range stringsValue(input, "services") {
+		svc := s.findService(r, clusterArn, name)
+		if svc == nil {
+			failures = append(failures, map[string]interface{}{"arn": name, "reason": "MISSING"})
+			continue
+		}
+		description := map[string]interface{}{}
+		for k, v := range svc.description {
+			description[k] = v
+		}
+		if slices.Contains(stringsValue(input, "include"), "TAGS") {
+			description["tags"] = tagsToList(s.ecsTags[svc.arn], "key", "value")
+		}
+		services = append(services, description)
+	}
+	return map[string]interface{}{"services": services, "failures": failures}, nil
+}
+
+func (s *Server) updateService(r *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	svc := s.findService(r, s.clusterArn(r, stringValue(input, "cluster")), stringValue(input, "service"))
+	if svc == nil || svc.description["status"] != "ACTIVE" {
+		return nil, newError(http.StatusBadRequest, "ServiceNotFoundException", "Service not found.")
+	}
+
+	for k, v := range input {
+		switch k {
+		case "cluster", "service", "forceNewDeployment":
+		case "taskDefinition":
+			td := s.findTaskDefinition(v.(string))
+			if td == nil {
+				return nil, clientException("TaskDefinition not found.")
+			}
+			svc.description[k] = td.arn
+		default:
+			svc.description[k] = v
+		}
+	}
+	return map[string]interface{}{"service": svc.description}, nil
+}
+
+func (s *Server) deleteService(r *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	svc := s.findService(r, s.clusterArn(r, stringValue(input, "cluster")), stringValue(input, "service"))
+	if svc == nil || svc.description["status"] != "ACTIVE" {
+		return nil, newError(http.StatusBadRequest, "ServiceNotFoundException", "Service not found.")
+	}
+
+	// Services are drained immediately, there are no tasks to stop
+	svc.description["status"] = "INACTIVE"
+	svc.description["desiredCount"] = 0
+	return map[string]interface{}{"service": svc.description}, nil
+}
+
+func (s *Server) listServices(r *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	clusterArn := s.clusterArn(r, stringValue(input, "cluster"))
+	arns := []string{}
+	for arn, svc := range s.services {
+		if svc.description["clusterArn"] == clusterArn && svc.description["status"] == "ACTIVE" {
+			arns = append(arns, arn)
+		}
+	}
+	sort.Strings(arns)
+	return map[string]interface{}{"serviceArns": arns}, nil
+}
+
+// listClusters lists the clusters of the services, clusters are not resources of the stand-in
+func (s *Server) listClusters(_ *http.Request, _ map[string]interface{}) (interface{}, *awsError) {
+	arns := []string{}
+	for _, svc := range s.services {
+		arn, _ := svc.description["clusterArn"].(string)
+		if !slices.Contains(arns, arn) {
+			arns = append(arns, arn)
+		}
+	}
+	sort.Strings(arns)
+	return map[string]interface{}{"clusterArns": arns}, nil
+}
+
+// findService resolves a service from its ARN or name within a cluster
+func (s *Server) findService(r *http.Request, clusterArn, name string) *service {
+	if !strings.HasPrefix(name, "arn:") {
+		name = fmt.Sprintf("arn:aws:ecs:%s:%s:service/%s/%s", requestRegion(r), AccountID, clusterName(clusterArn), name)
+	}
+	return s.services[name]
+}
+
+// clusterArn returns the ARN of a cluster given by name or ARN, defaulting to the `default` cluster
+func (s *Server) clusterArn(r *http.Request, cluster string) string {
+	if strings.HasPrefix(cluster, "arn:") {
+		return cluster
+	}
+	if cluster == "" {
+		cluster = "default"
+	}
+	return fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", requestRegion(r), AccountID, cluster)
+}
+
+func clusterName(clusterArn string) string {
+	return clusterArn[strings.LastIndex(clusterArn, "/")+1:]
+}
+
+func (s *Server) listECSTags(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	tags, found := s.ecsTags[stringValue(input, "resourceArn")]
+	if !found {
+		return nil, clientException("The specified resource could not be found.")
+	}
+	return map[string]interface{}{"tags": tagsToList(tags, "key", "value")}, nil
+}
+
+func (s *Server) tagECSResource(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	tags, found := s.ecsTags[stringValue(input, "resourceArn")]
+	if !found {
+		return nil, clientException("The specified resource could not be found.")
+	}
+	for k, v := range tagsFromList(input["tags"], "key", "value") {
+		tags[k] = v
+	}
+	return map[string]interface{}{}, nil
+}
+
+func (s *Server) untagECSResource(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	tags, found := s.ecsTags[stringValue(input, "resourceArn")]
+	if !found {
+		return nil, clientException("The specified resource could not be found.")
+	}
+	for _, k := range stringsValue(input, "tagKeys") {
+		delete(tags, k)
+	}
+	return map[string]interface{}{}, nil
+}
+
+// stringValue returns a string member of a decoded JSON object
+func stringValue(input map[string]interface{}, key string) string {
+	v, _ := input[key].(string)
+	return v
+}
+
+// stringsValue returns a list of strings member of a decoded JSON object
+func stringsValue(input map[string]interface{}, key string) []string {
+	list, _ := input[key].([]interface{})
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		if str, ok := v.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+// tagsFromList converts a decoded list of tag objects to a map
+func tagsFromList(list interface{}, keyField, valueField string) map[string]string {
+	tags := map[string]string{}
+	items, _ := list.([]interface{})
+	for _, item := range items {
+		if tag, ok := item.(map[string]interface{}); ok {
+			tags[stringValue(tag, keyField)] = stringValue(tag, valueField)
+		}
+	}
+	return tags
+}
+
+// tagsToList converts tags to a list of tag objects sorted by key
+func tagsToList(tags map[string]string, keyField, valueField string) []map[string]string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]map[string]string, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, map[string]string{keyField: k, valueField: tags[k]})
+	}
+	return result
+}
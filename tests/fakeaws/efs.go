@@ -0,0 +1,262 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package fakeaws
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	efsPathPrefix = "/2015-02-01/"
+	restJSON      = "application/json"
+)
+
+// fileSystem is an EFS file system, kept as the API describes it
+type fileSystem struct {
+	id          string
+	description map[string]interface{}
+	tags        map[string]string
+	lifecycle   []interface{}
+}
+
+// accessPoint is an EFS access point, kept as the API describes it
+type accessPoint struct {
+	id          string
+	description map[string]interface{}
+	tags        map[string]string
+}
+
+// serveEFS routes the EFS REST API: file-systems, access-points, mount-targets and resource-tags
+func (s *Server) serveEFS(w http.ResponseWriter, r *http.Request) {
+	input := map[string]interface{}{}
+	if err := decodeJSON(r, &input); err != nil {
+		writeEFSError(w, err)
+		return
+	}
+
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, efsPathPrefix), "/"), "/")
+	status := http.StatusOK
+	var output interface{}
+	var err *awsError
+
+	switch {
+	case path[0] == "file-systems" && len(path) == 1 && r.Method == http.MethodPost:
+		status = http.StatusCreated
+		output, err = s.createFileSystem(r, input)
+	case path[0] == "file-systems" && len(path) == 1 && r.Method == http.MethodGet:
+		output, err = s.describeFileSystems(r)
+	case path[0] == "file-systems" && len(path) == 2 && r.Method == http.MethodDelete:
+		status = http.StatusNoContent
+		err = s.deleteFileSystem(path[1])
+	case path[0] == "file-systems" && len(path) == 3 && path[2] == "lifecycle-configuration":
+		output, err = s.lifecycleConfiguration(r, path[1], input)
+	case path[0] == "access-points" && len(path) == 1 && r.Method == http.MethodPost:
+		output, err = s.createAccessPoint(r, input)
+	case path[0] == "access-points" && len(path) == 1 && r.Method == http.MethodGet:
+		output, err = s.describeAccessPoints(r)
+	case path[0] == "access-points" && len(path) == 2 && r.Method == http.MethodDelete:
+		status = http.StatusNoContent
+		err = s.deleteAccessPoint(path[1])
+	case path[0] == "mount-targets" && r.Method == http.MethodGet:
+		// Mount targets are not supported
+		output = map[string]interface{}{"MountTargets": []interface{}{}}
+	case path[0] == "resource-tags" && len(path) == 2:
+		status, output, err = s.efsTags(r, path[1], input)
+	default:
+		err = newError(http.StatusNotFound, "UnknownOperationException", "EFS request %s %s is not supported", r.Method, r.URL.Path)
+	}
+
+	if err != nil {
+		writeEFSError(w, err)
+		return
+	}
+	if output == nil {
+		w.WriteHeader(status)
+		return
+	}
+	writeJSON(w, restJSON, status, output)
+}
+
+// writeEFSError writes an error of the REST JSON protocol
+func writeEFSError(w http.ResponseWriter, err *awsError) {
+	w.Header().Set("X-Amzn-ErrorType", err.code)
+	writeJSON(w, restJSON, err.status, map[string]string{
+		"ErrorCode": err.code,
+		"Message":   err.message,
+	})
+}
+
+func fileSystemNotFound(id string) *awsError {
+	return newError(http.StatusNotFound, "FileSystemNotFound", "File system '%s' does not exist.", id)
+}
+
+func (s *Server) createFileSystem(r *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	token := stringValue(input, "CreationToken")
+	for _, fs := range s.fileSystems {
+		if fs.description["CreationToken"] == token {
+			return nil, newError(http.StatusConflict, "FileSystemAlreadyExists", "File system '%s' already exists with creation token '%s'", fs.id, token)
+		}
+	}
+
+	id := strings.ToLower(s.nextID("fs-"))
+	description := map[string]interface{}{}
+	for k, v := range input {
+		description[k] = v
+	}
+	tags := tagsFromList(input["Tags"], "Key", "Value")
+	description["OwnerId"] = AccountID
+	description["FileSystemId"] = id
+	description["FileSystemArn"] = fmt.Sprintf("arn:aws:elasticfilesystem:%s:%s:file-system/%s", requestRegion(r), AccountID, id)
+	description["CreationTime"] = epochSeconds(time.Now())
+	description["LifeCycleState"] = "available"
+	description["NumberOfMountTargets"] = 0
+	description["SizeInBytes"] = map[string]interface{}{"Value": 6144}
+	description["Tags"] = tagsToList(tags, "Key", "Value")
+	if name, found := tags["Name"]; found {
+		description["Name"] = name
+	}
+	if _, found := description["PerformanceMode"]; !found {
+		description["PerformanceMode"] = "generalPurpose"
+	}
+	if _, found := description["ThroughputMode"]; !found {
+		description["ThroughputMode"] = "bursting"
+	}
+	if _, found := description["Encrypted"]; !found {
+		description["Encrypted"] = false
+	}
+
+	s.fileSystems[id] = &fileSystem{id: id, description: description, tags: tags, lifecycle: []interface{}{}}
+	return description, nil
+}
+
+func (s *Server) describeFileSystems(r *http.Request) (interface{}, *awsError) {
+	query := r.URL.Query()
+	fileSystems := []interface{}{}
+	if id := query.Get("FileSystemId"); id != "" {
+		fs, found := s.fileSystems[id]
+		if !found {
+			return nil, fileSystemNotFound(id)
+		}
+		fileSystems = append(fileSystems, fs.description)
+	} else {
+		for _, fs := range s.fileSystems {
+			if token := query.Get("CreationToken"); token == "" || fs.description["CreationToken"] == token {
+				fileSystems = append(fileSystems, fs.description)
+			}
+		}
+	}
+	return map[string]interface{}{"FileSystems": fileSystems}, nil
+}
+
+func (s *Server) deleteFileSystem(id string) *awsError {
+	if _, found := s.fileSystems[id]; !found {
+		return fileSystemNotFound(id)
+	}
+	for _, ap := range s.accessPoints {
+		if ap.description["FileSystemId"] == id {
+			return newError(http.StatusConflict, "FileSystemInUse", "File system '%s' has access points.", id)
+		}
+	}
+	delete(s.fileSystems, id)
+	return nil
+}
+
+func (s *Server) lifecycleConfiguration(r *http.Request, id string, input map[string]interface{}) (interface{}, *awsError) {
+	fs, found := s.fileSystems[id]
+	if !found {
+		return nil, fileSystemNotFound(id)
+	}
+	if r.Method == http.MethodPut {
+		fs.lifecycle, _ = input["LifecyclePolicies"].([]interface{})
+		if fs.lifecycle == nil {
+			fs.lifecycle = []interface{}{}
+		}
+	}
+	return map[string]interface{}{"LifecyclePolicies": fs.lifecycle}, nil
+}
+
+func (s *Server) createAccessPoint(r *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	fileSystemID := stringValue(input, "FileSystemId")
+	if _, found := s.fileSystems[fileSystemID]; !found {
+		return nil, fileSystemNotFound(fileSystemID)
+	}
+
+	id := strings.ToLower(s.nextID("fsap-"))
+	description := map[string]interface{}{}
+	for k, v := range input {
+		description[k] = v
+	}
+	tags := tagsFromList(input["Tags"], "Key", "Value")
+	description["AccessPointId"] = id
+	description["AccessPointArn"] = fmt.Sprintf("arn:aws:elasticfilesystem:%s:%s:access-point/%s", requestRegion(r), AccountID, id)
+	description["OwnerId"] = AccountID
+	description["LifeCycleState"] = "available"
+	description["Tags"] = tagsToList(tags, "Key", "Value")
+	if name, found := tags["Name"]; found {
+		description["Name"] = name
+	}
+
+	s.accessPoints[id] = &accessPoint{id: id, description: description, tags: tags}
+	return description, nil
+}
+
+func (s *Server) describeAccessPoints(r *http.Request) (interface{}, *awsError) {
+	query := r.URL.Query()
+	accessPoints := []interface{}{}
+	if id := query.Get("AccessPointId"); id != "" {
+		ap, found := s.accessPoints[id]
+		if !found {
+			return nil, newError(http.StatusNotFound, "AccessPointNotFound", "Access point '%s' does not exist.", id)
+		}
+		accessPoints = append(accessPoints, ap.description)
+	} else {
+		for _, ap := range s.accessPoints {
+			if fileSystemID := query.Get("FileSystemId"); fileSystemID == "" || ap.description["FileSystemId"] == fileSystemID {
+				accessPoints = append(accessPoints, ap.description)
+			}
+		}
+	}
+	return map[string]interface{}{"AccessPoints": accessPoints}, nil
+}
+
+func (s *Server) deleteAccessPoint(id string) *awsError {
+	if _, found := s.accessPoints[id]; !found {
+		return newError(http.StatusNotFound, "AccessPointNotFound", "Access point '%s' does not exist.", id)
+	}
+	delete(s.accessPoints, id)
+	return nil
+}
+
+// efsTags lists, adds or removes the tags of a file system or access point
+func (s *Server) efsTags(r *http.Request, id string, input map[string]interface{}) (int, interface{}, *awsError) {
+	var description map[string]interface{}
+	var tags map[string]string
+	if fs, found := s.fileSystems[id]; found {
+		description, tags = fs.description, fs.tags
+	} else if ap, found := s.accessPoints[id]; found {
+		description, tags = ap.description, ap.tags
+	} else {
+		return 0, nil, newError(http.StatusNotFound, "FileSystemNotFound", "Resource '%s' does not exist.", id)
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, map[string]interface{}{"Tags": tagsToList(tags, "Key", "Value")}, nil
+	case http.MethodPost:
+		for k, v := range tagsFromList(input["Tags"], "Key", "Value") {
+			tags[k] = v
+		}
+	case http.MethodDelete:
+		for _, k := range r.URL.Query()["tagKeys"] {
+			delete(tags, k)
+		}
+	}
+	description["Tags"] = tagsToList(tags, "Key", "Value")
+	return http.StatusOK, nil, nil
+}
@@ -0,0 +1,715 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package fakeaws
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	iamVersion   = "2010-05-08"
+	iamNamespace = "https://iam.amazonaws.com/doc/2010-05-08/"
+
+	// awsManagedPolicyPrefix prefixes the policies owned by AWS, which always exist
+	awsManagedPolicyPrefix = "arn:aws:iam::aws:policy/"
+)
+
+// role is an IAM role with its attached managed policies
+type role struct {
+	name                     string
+	path                     string
+	id                       string
+	arn                      string
+	description              string
+	assumeRolePolicyDocument string
+	maxSessionDuration       int
+	createDate               time.Time
+	tags                     map[string]string
+	attachedPolicies         []string
+}
+
+// policy is an IAM customer managed policy and its versions
+type policy struct {
+	name             string
+	path             string
+	id               string
+	arn              string
+	description      string
+	createDate       time.Time
+	updateDate       time.Time
+	tags             map[string]string
+	versions         []*policyVersion
+	defaultVersionID string
+}
+
+type policyVersion struct {
+	id         string
+	document   string
+	createDate time.Time
+}
+
+// XML views of the IAM resources
+
+type xmlTag struct {
+	Key   string
+	Value string
+}
+
+type xmlRole struct {
+	Path                     string
+	RoleName                 string
+	RoleId                   string
+	Arn                      string
+	CreateDate               string
+	AssumeRolePolicyDocument string
+	Description              string `xml:",omitempty"`
+	MaxSessionDuration       int
+	Tags                     []xmlTag `xml:"Tags>member,omitempty"`
+}
+
+type xmlPolicy struct {
+	PolicyName                    string
+	PolicyId                      string
+	Arn                           string
+	Path                          string
+	DefaultVersionId              string
+	AttachmentCount               int
+	PermissionsBoundaryUsageCount int
+	IsAttachable                  bool
+	Description                   string `xml:",omitempty"`
+	CreateDate                    string
+	UpdateDate                    string
+	Tags                          []xmlTag `xml:"Tags>member,omitempty"`
+}
+
+type xmlPolicyVersion struct {
+	Document         string `xml:",omitempty"`
+	VersionId        string
+	IsDefaultVersion bool
+	CreateDate       string
+}
+
+type xmlAttachedPolicy struct {
+	PolicyName string
+	PolicyArn  string
+}
+
+type iamHandler func(s *Server, input url.Values) (interface{}, *awsError)
+
+var iamHandlers = map[string]iamHandler{
+	"CreateRole":                  (*Server).createRole,
+	"GetRole":                     (*Server).getRole,
+	"DeleteRole":                  (*Server).deleteRole,
+	"UpdateRole":                  (*Server).updateRole,
+	"UpdateAssumeRolePolicy":      (*Server).updateAssumeRolePolicy,
+	"ListRoles":                   (*Server).listRoles,
+	"ListRoleTags":                (*Server).listRoleTags,
+	"TagRole":                     (*Server).tagRole,
+	"UntagRole":                   (*Server).untagRole,
+	"ListRolePolicies":            (*Server).listRolePolicies,
+	"ListAttachedRolePolicies":    (*Server).listAttachedRolePolicies,
+	"ListInstanceProfilesForRole": (*Server).listInstanceProfilesForRole,
+	"AttachRolePolicy":            (*Server).attachRolePolicy,
+	"DetachRolePolicy":            (*Server).detachRolePolicy,
+	"CreatePolicy":                (*Server).createPolicy,
+	"GetPolicy":                   (*Server).getPolicy,
+	"DeletePolicy":                (*Server).deletePolicy,
+	"ListPolicies":                (*Server).listPolicies,
+	"ListPolicyTags":              (*Server).listPolicyTags,
+	"ListEntitiesForPolicy":       (*Server).listEntitiesForPolicy,
+	"CreatePolicyVersion":         (*Server).createPolicyVersion,
+	"GetPolicyVersion":            (*Server).getPolicyVersion,
+	"ListPolicyVersions":          (*Server).listPolicyVersions,
+	"DeletePolicyVersion":         (*Server).deletePolicyVersion,
+}
+
+func (s *Server) serveIAM(w http.ResponseWriter, input url.Values) {
+	action := input.Get("Action")
+	handler, found := iamHandlers[action]
+	if !found {
+		writeQueryError(w, iamNamespace, newError(http.StatusBadRequest, "InvalidAction", "IAM action %s is not supported", action))
+		return
+	}
+
+	result, err := handler(s, input)
+	if err != nil {
+		writeQueryError(w, iamNamespace, err)
+		return
+	}
+	writeQueryResponse(w, iamNamespace, action, result)
+}
+
+// writeQueryResponse writes a response of the AWS query protocol.
+// The fields of result are the children of the <ActionResult> element.
+func writeQueryResponse(w http.ResponseWriter, namespace, action string, result interface{}) {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, `<%sResponse xmlns="%s">`, action, namespace)
+	if result != nil {
+		encoder := xml.NewEncoder(&body)
+		if err := encoder.EncodeElement(result, xml.StartElement{Name: xml.Name{Local: action + "Result"}}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = encoder.Flush()
+	}
+	fmt.Fprintf(&body, `<ResponseMetadata><RequestId>%s</RequestId></ResponseMetadata></%sResponse>`, requestID(), action)
+
+	w.Header().Set("Content-Type", "text/xml")
+	_, _ = w.Write(body.Bytes())
+}
+
+// writeQueryError writes an error of the AWS query protocol
+func writeQueryError(w http.ResponseWriter, namespace string, err *awsError) {
+	errorType := "Sender"
+	if err.status >= http.StatusInternalServerError {
+		errorType = "Receiver"
+	}
+
+	var message bytes.Buffer
+	_ = xml.EscapeText(&message, []byte(err.message))
+
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(err.status)
+	fmt.Fprintf(w, `<ErrorResponse xmlns="%s"><Error><Type>%s</Type><Code>%s</Code><Message>%s</Message></Error><RequestId>%s</RequestId></ErrorResponse>`,
+		namespace, errorType, err.code, message.String(), requestID())
+}
+
+func requestID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+func noSuchEntity(format string, args ...interface{}) *awsError {
+	return newError(http.StatusNotFound, "NoSuchEntity", format, args...)
+}
+
+func iamTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// queryTags decodes the `Tags.member.N.Key` and `Tags.member.N.Value` parameters
+func queryTags(input url.Values) map[string]string {
+	tags := map[string]string{}
+	for i := 1; input.Has(fmt.Sprintf("Tags.member.%d.Key", i)); i++ {
+		tags[input.Get(fmt.Sprintf("Tags.member.%d.Key", i))] = input.Get(fmt.Sprintf("Tags.member.%d.Value", i))
+	}
+	return tags
+}
+
+// queryList decodes the `<name>.member.N` parameters
+func queryList(input url.Values, name string) []string {
+	var list []string
+	for i := 1; input.Has(fmt.Sprintf("%s.member.%d", name, i)); i++ {
+		list = append(list, input.Get(fmt.Sprintf("%s.member.%d", name, i)))
+	}
+	return list
+}
+
+func xmlTags(tags map[string]string) []xmlTag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]xmlTag, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, xmlTag{Key: k, Value: tags[k]})
+	}
+	return result
+}
+
+// iamPath validates and defaults the path of a role or policy
+func iamPath(input url.Values) (string, *awsError) {
+	path := input.Get("Path")
+	if path == "" {
+		return "/", nil
+	}
+	if !strings.HasPrefix(path, "/") || !strings.HasSuffix(path, "/") {
+		return "", newError(http.StatusBadRequest, "ValidationError", "The specified value for path is invalid. It must begin and end with / and contain only alphanumeric characters and/or / characters.")
+	}
+	return path, nil
+}
+
+func (r *role) view() xmlRole {
+	return xmlRole{
+		Path:     r.path,
+		RoleName: r.name,
+		RoleId:   r.id,
+		Arn:      r.arn,
+		// IAM returns URL encoded policy documents
+		AssumeRolePolicyDocument: url.QueryEscape(r.assumeRolePolicyDocument),
+		Description:              r.description,
+		MaxSessionDuration:       r.maxSessionDuration,
+		CreateDate:               iamTimestamp(r.createDate),
+		Tags:                     xmlTags(r.tags),
+	}
+}
+
+func (s *Server) findRole(input url.Values) (*role, *awsError) {
+	name := input.Get("RoleName")
+	r, found := s.roles[name]
+	if !found {
+		return nil, noSuchEntity("The role with name %s cannot be found.", name)
+	}
+	return r, nil
+}
+
+func (s *Server) createRole(input url.Values) (interface{}, *awsError) {
+	name := input.Get("RoleName")
+	if name == "" || len(name) > 64 {
+		return nil, newError(http.StatusBadRequest, "ValidationError", "1 validation error detected: Value '%s' at 'roleName' failed to satisfy constraint: Member must have length between 1 and 64", name)
+	}
+	if _, found := s.roles[name]; found {
+		return nil, newError(http.StatusConflict, "EntityAlreadyExists", "Role with name %s already exists.", name)
+	}
+	path, err := iamPath(input)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSessionDuration := 3600
+	if v, convErr := strconv.Atoi(input.Get("MaxSessionDuration")); convErr == nil {
+		maxSessionDuration = v
+	}
+
+	r := &role{
+		name:                     name,
+		path:                     path,
+		id:                       s.nextID("AROA"),
+		arn:                      fmt.Sprintf("arn:aws:iam::%s:role%s%s", AccountID, path, name),
+		description:              input.Get("Description"),
+		assumeRolePolicyDocument: input.Get("AssumeRolePolicyDocument"),
+		maxSessionDuration:       maxSessionDuration,
+		createDate:               time.Now(),
+		tags:                     queryTags(input),
+	}
+	s.roles[name] = r
+	return struct{ Role xmlRole }{r.view()}, nil
+}
+
+func (s *Server) getRole(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+	return struct{ Role xmlRole }{r.view()}, nil
+}
+
+func (s *Server) deleteRole(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.attachedPolicies) > 0 {
+		return nil, newError(http.StatusConflict, "DeleteConflict", "Cannot delete entity, must detach all policies first.")
+	}
+	delete(s.roles, r.name)
+	return nil, nil
+}
+
+func (s *Server) updateRole(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+	if input.Has("Description") {
+		r.description = input.Get("Description")
+	}
+	if v, convErr := strconv.Atoi(input.Get("MaxSessionDuration")); convErr == nil {
+		r.maxSessionDuration = v
+	}
+	return struct{}{}, nil
+}
+
+func (s *Server) updateAssumeRolePolicy(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+	r.assumeRolePolicyDocument = input.Get("PolicyDocument")
+	return nil, nil
+}
+
+func (s *Server) listRoles(input url.Values) (interface{}, *awsError) {
+	prefix := input.Get("PathPrefix")
+	names := make([]string, 0, len(s.roles))
+	for name, r := range s.roles {
+		if strings.HasPrefix(r.path, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	roles := make([]xmlRole, 0, len(names))
+	for _, name := range names {
+		view := s.roles[name].view()
+		// ListRoles does not return tags
+		view.Tags = nil
+		roles = append(roles, view)
+	}
+	return struct {
+		Roles       []xmlRole `xml:"Roles>member"`
+		IsTruncated bool
+	}{Roles: roles}, nil
+}
+
+func (s *Server) listRoleTags(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		Tags        []xmlTag `xml:"Tags>member"`
+		IsTruncated bool
+	}{Tags: xmlTags(r.tags)}, nil
+}
+
+func (s *Server) tagRole(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range queryTags(input) {
+		r.tags[k] = v
+	}
+	return nil, nil
+}
+
+func (s *Server) untagRole(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range queryList(input, "TagKeys") {
+		delete(r.tags, k)
+	}
+	return nil, nil
+}
+
+func (s *Server) listRolePolicies(input url.Values) (interface{}, *awsError) {
+	if _, err := s.findRole(input); err != nil {
+		return nil, err
+	}
+	// Inline policies are not supported
+	return struct {
+		PolicyNames []string `xml:"PolicyNames>member"`
+		IsTruncated bool
+	}{}, nil
+}
+
+func (s *Server) listAttachedRolePolicies(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+
+	attached := make([]xmlAttachedPolicy, 0, len(r.attachedPolicies))
+	for _, arn := range r.attachedPolicies {
+		attached = append(attached, xmlAttachedPolicy{PolicyName: arn[strings.LastIndex(arn, "/")+1:], PolicyArn: arn})
+	}
+	return struct {
+		AttachedPolicies []xmlAttachedPolicy `xml:"AttachedPolicies>member"`
+		IsTruncated      bool
+	}{AttachedPolicies: attached}, nil
+}
+
+func (s *Server) listInstanceProfilesForRole(input url.Values) (interface{}, *awsError) {
+	if _, err := s.findRole(input); err != nil {
+		return nil, err
+	}
+	// Instance profiles are not supported
+	return struct {
+		InstanceProfiles []struct{} `xml:"InstanceProfiles>member"`
+		IsTruncated      bool
+	}{}, nil
+}
+
+func (s *Server) attachRolePolicy(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+	arn := input.Get("PolicyArn")
+	p, found := s.policies[arn]
+	if !found && !strings.HasPrefix(arn, awsManagedPolicyPrefix) {
+		return nil, noSuchEntity("Policy %s does not exist or is not attachable.", arn)
+	}
+
+	for _, attached := range r.attachedPolicies {
+		if attached == arn {
+			return nil, nil
+		}
+	}
+	r.attachedPolicies = append(r.attachedPolicies, arn)
+	if p != nil {
+		p.updateDate = time.Now()
+	}
+	return nil, nil
+}
+
+func (s *Server) detachRolePolicy(input url.Values) (interface{}, *awsError) {
+	r, err := s.findRole(input)
+	if err != nil {
+		return nil, err
+	}
+	arn := input.Get("PolicyArn")
+	for i, attached := range r.attachedPolicies {
+		if attached == arn {
+			r.attachedPolicies = append(r.attachedPolicies[:i], r.attachedPolicies[i+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, noSuchEntity("Policy %s was not found.", arn)
+}
+
+// attachmentCount returns the number of roles a policy is attached to
+func (s *Server) attachmentCount(arn string) int {
+	count := 0
+	for _, r := range s.roles {
+		for _, attached := range r.attachedPolicies {
+			if attached == arn {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func (s *Server) policyView(p *policy) xmlPolicy {
+	return xmlPolicy{
+		PolicyName:       p.name,
+		PolicyId:         p.id,
+		Arn:              p.arn,
+		Path:             p.path,
+		DefaultVersionId: p.defaultVersionID,
+		AttachmentCount:  s.attachmentCount(p.arn),
+		IsAttachable:     true,
+		Description:      p.description,
+		CreateDate:       iamTimestamp(p.createDate),
+		UpdateDate:       iamTimestamp(p.updateDate),
+		Tags:             xmlTags(p.tags),
+	}
+}
+
+func (v *policyVersion) view(p *policy, withDocument bool) xmlPolicyVersion {
+	view := xmlPolicyVersion{
+		VersionId:        v.id,
+		IsDefaultVersion: v.id == p.defaultVersionID,
+		CreateDate:       iamTimestamp(v.createDate),
+	}
+	if withDocument {
+		// IAM returns URL encoded policy documents
+		view.Document = url.QueryEscape(v.document)
+	}
+	return view
+}
+
+func (s *Server) findPolicy(input url.Values) (*policy, *awsError) {
+	arn := input.Get("PolicyArn")
+	p, found := s.policies[arn]
+	if !found {
+		return nil, noSuchEntity("Policy %s does not exist or is not attachable.", arn)
+	}
+	return p, nil
+}
+
+func (s *Server) createPolicy(input url.Values) (interface{}, *awsError) {
+	name := input.Get("PolicyName")
+	if name == "" || len(name) > 128 {
+		return nil, newError(http.StatusBadRequest, "ValidationError", "1 validation error detected: Value '%s' at 'policyName' failed to satisfy constraint: Member must have length between 1 and 128", name)
+	}
+	path, err := iamPath(input)
+	if err != nil {
+		return nil, err
+	}
+	arn := fmt.Sprintf("arn:aws:iam::%s:policy%s%s", AccountID, path, name)
+	if _, found := s.policies[arn]; found {
+		return nil, newError(http.StatusConflict, "EntityAlreadyExists", "A policy called %s already exists. Duplicate names are not allowed.", name)
+	}
+
+	now := time.Now()
+	p := &policy{
+		name:             name,
+		path:             path,
+		id:               s.nextID("ANPA"),
+		arn:              arn,
+		description:      input.Get("Description"),
+		createDate:       now,
+		updateDate:       now,
+		tags:             queryTags(input),
+		versions:         []*policyVersion{{id: "v1", document: input.Get("PolicyDocument"), createDate: now}},
+		defaultVersionID: "v1",
+	}
+	s.policies[arn] = p
+	return struct{ Policy xmlPolicy }{s.policyView(p)}, nil
+}
+
+func (s *Server) getPolicy(input url.Values) (interface{}, *awsError) {
+	p, err := s.findPolicy(input)
+	if err != nil {
+		return nil, err
+	}
+	return struct{ Policy xmlPolicy }{s.policyView(p)}, nil
+}
+
+func (s *Server) deletePolicy(input url.Values) (interface{}, *awsError) {
+	p, err := s.findPolicy(input)
+	if err != nil {
+		return nil, err
+	}
+	if s.attachmentCount(p.arn) > 0 {
+		return nil, newError(http.StatusConflict, "DeleteConflict", "Cannot delete a policy attached to entities.")
+	}
+	if len(p.versions) > 1 {
+		return nil, newError(http.StatusConflict, "DeleteConflict", "This policy has more than one version. Before you delete a policy, you must delete the policy's versions. The default version is deleted with the policy.")
+	}
+	delete(s.policies, p.arn)
+	return nil, nil
+}
+
+func (s *Server) listPolicies(input url.Values) (interface{}, *awsError) {
+	// Only customer managed policies are kept, AWS managed ones are never listed
+	if input.Get("Scope") == "AWS" {
+		return struct {
+			Policies    []xmlPolicy `xml:"Policies>member"`
+			IsTruncated bool
+		}{}, nil
+	}
+
+	prefix := input.Get("PathPrefix")
+	arns := make([]string, 0, len(s.policies))
+	for arn, p := range s.policies {
+		if strings.HasPrefix(p.path, prefix) && (input.Get("OnlyAttached") != "true" || s.attachmentCount(arn) > 0) {
+			arns = append(arns, arn)
+		}
+	}
+	sort.Strings(arns)
+
+	policies := make([]xmlPolicy, 0, len(arns))
+	for _, arn := range arns {
+		view := s.policyView(s.policies[arn])
+		// ListPolicies does not return tags
+		view.Tags = nil
+		policies = append(policies, view)
+	}
+	return struct {
+		Policies    []xmlPolicy `xml:"Policies>member"`
+		IsTruncated bool
+	}{Policies: policies}, nil
+}
+
+func (s *Server) listEntitiesForPolicy(input url.Values) (interface{}, *awsError) {
+	p, err := s.findPolicy(input)
+	if err != nil {
+		return nil, err
+	}
+
+	type xmlPolicyRole struct {
+		RoleName string
+		RoleId   string
+	}
+	roles := []xmlPolicyRole{}
+	for _, r := range s.roles {
+		if slices.Contains(r.attachedPolicies, p.arn) {
+			roles = append(roles, xmlPolicyRole{RoleName: r.name, RoleId: r.id})
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].RoleName < roles[j].RoleName })
+
+	// Policies are only attached to roles
+	return struct {
+		PolicyRoles  []xmlPolicyRole `xml:"PolicyRoles>member"`
+		PolicyGroups []struct{}      `xml:"PolicyGroups>member"`
+		PolicyUsers  []struct{}      `xml:"PolicyUsers>member"`
+		IsTruncated  bool
+	}{PolicyRoles: roles}, nil
+}
+
+func (s *Server) listPolicyTags(input url.Values) (interface{}, *awsError) {
+	p, err := s.findPolicy(input)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		Tags        []xmlTag `xml:"Tags>member"`
+		IsTruncated bool
+	}{Tags: xmlTags(p.tags)}, nil
+}
+
+func (s *Server) createPolicyVersion(input url.Values) (interface{}, *awsError) {
+	p, err := s.findPolicy(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.versions) >= 5 {
+		return nil, newError(http.StatusConflict, "LimitExceeded", "A managed policy can have up to 5 versions.")
+	}
+
+	last := p.versions[len(p.versions)-1].id
+	n, _ := strconv.Atoi(strings.TrimPrefix(last, "v"))
+	v := &policyVersion{id: fmt.Sprintf("v%d", n+1), document: input.Get("PolicyDocument"), createDate: time.Now()}
+	p.versions = append(p.versions, v)
+	if input.Get("SetAsDefault") == "true" {
+		p.defaultVersionID = v.id
+	}
+	p.updateDate = v.createDate
+	return struct{ PolicyVersion xmlPolicyVersion }{v.view(p, false)}, nil
+}
+
+func (s *Server) getPolicyVersion(input url.Values) (interface{}, *awsError) {
+	p, err := s.findPolicy(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range p.versions {
+		if v.id == input.Get("VersionId") {
+			return struct{ PolicyVersion xmlPolicyVersion }{v.view(p, true)}, nil
+		}
+	}
+	return nil, noSuchEntity("Policy %s version %s does not exist.", p.arn, input.Get("VersionId"))
+}
+
+func (s *Server) listPolicyVersions(input url.Values) (interface{}, *awsError) {
+	p, err := s.findPolicy(input)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]xmlPolicyVersion, 0, len(p.versions))
+	for _, v := range p.versions {
+		versions = append(versions, v.view(p, false))
+	}
+	return struct {
+		Versions    []xmlPolicyVersion `xml:"Versions>member"`
+		IsTruncated bool
+	}{Versions: versions}, nil
+}
+
+func (s *Server) deletePolicyVersion(input url.Values) (interface{}, *awsError) {
+	p, err := s.findPolicy(input)
+	if err != nil {
+		return nil, err
+	}
+	id := input.Get("VersionId")
+	if id == p.defaultVersionID {
+		return nil, newError(http.StatusConflict, "DeleteConflict", "Cannot delete the default version of a policy.")
+	}
+	for i, v := range p.versions {
+		if v.id == id {
+			p.versions = append(p.versions[:i], p.versions[i+1:]...)
+			return nil, nil
+		}
+	}
+	return nil, noSuchEntity("Policy %s version %s does not exist.", p.arn, id)
+}
@@ -0,0 +1,236 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package fakeaws
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const secretsManagerTargetPrefix = "secretsmanager."
+
+// secret is a Secrets Manager secret with its current value
+type secret struct {
+	arn          string
+	name         string
+	description  string
+	kmsKeyID     string
+	secretString string
+	versionID    string
+	createdDate  time.Time
+	deletedDate  *time.Time
+	tags         map[string]string
+}
+
+type secretsManagerHandler func(s *Server, r *http.Request, input map[string]interface{}) (interface{}, *awsError)
+
+var secretsManagerHandlers = map[string]secretsManagerHandler{
+	"CreateSecret":      (*Server).createSecret,
+	"DescribeSecret":    (*Server).describeSecret,
+	"GetSecretValue":    (*Server).getSecretValue,
+	"PutSecretValue":    (*Server).putSecretValue,
+	"GetResourcePolicy": (*Server).getSecretResourcePolicy,
+	"TagResource":       (*Server).tagSecret,
+	"UntagResource":     (*Server).untagSecret,
+	"DeleteSecret":      (*Server).deleteSecret,
+}
+
+func (s *Server) serveSecretsManager(w http.ResponseWriter, r *http.Request, operation string) {
+	handler, found := secretsManagerHandlers[operation]
+	if !found {
+		writeJSONError(w, newError(http.StatusBadRequest, "UnknownOperationException", "Secrets Manager operation %s is not supported", operation))
+		return
+	}
+
+	input := map[string]interface{}{}
+	if err := decodeJSON(r, &input); err != nil {
+		writeJSONError(w, err)
+		return
+	}
+
+	output, err := handler(s, r, input)
+	if err != nil {
+		writeJSONError(w, err)
+		return
+	}
+	writeJSON(w, amzJSON11, http.StatusOK, output)
+}
+
+func resourceNotFound() *awsError {
+	return newError(http.StatusBadRequest, "ResourceNotFoundException", "Secrets Manager can't find the specified secret.")
+}
+
+// findSecret resolves a secret from its ARN or name. Secrets scheduled for deletion are
+// only returned if deleted is true.
+func (s *Server) findSecret(id string, deleted bool) (*secret, *awsError) {
+	for _, sec := range s.secrets {
+		if sec.arn == id || sec.name == id {
+			if sec.deletedDate != nil && !deleted {
+				return nil, newError(http.StatusBadRequest, "InvalidRequestException", "You can't perform this operation on the secret because it was marked for deletion.")
+			}
+			return sec, nil
+		}
+	}
+	return nil, resourceNotFound()
+}
+
+func (s *Server) createSecret(r *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	name := stringValue(input, "Name")
+	if name == "" {
+		return nil, newError(http.StatusBadRequest, "InvalidParameterException", "You must provide a name for the secret.")
+	}
+	if _, found := s.secrets[name]; found {
+		return nil, newError(http.StatusBadRequest, "ResourceExistsException", "The operation failed because the secret %s already exists.", name)
+	}
+
+	// Secret ARNs end with a random 6 characters suffix
+	suffix := strings.ToLower(s.nextID(""))
+	sec := &secret{
+		arn:          fmt.Sprintf("arn:aws:secretsmanager:%s:%s:secret:%s-%s", requestRegion(r), AccountID, name, suffix[len(suffix)-6:]),
+		name:         name,
+		description:  stringValue(input, "Description"),
+		kmsKeyID:     stringValue(input, "KmsKeyId"),
+		secretString: stringValue(input, "SecretString"),
+		createdDate:  time.Now(),
+		tags:         tagsFromList(input["Tags"], "Key", "Value"),
+	}
+	output := map[string]interface{}{"ARN": sec.arn, "Name": sec.name}
+	if _, found := input["SecretString"]; found {
+		sec.versionID = s.versionID(input)
+		output["VersionId"] = sec.versionID
+	}
+	s.secrets[name] = sec
+	return output, nil
+}
+
+func (s *Server) describeSecret(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	sec, err := s.findSecret(stringValue(input, "SecretId"), true)
+	if err != nil {
+		return nil, err
+	}
+
+	output := map[string]interface{}{
+		"ARN":             sec.arn,
+		"Name":            sec.name,
+		"CreatedDate":     epochSeconds(sec.createdDate),
+		"RotationEnabled": false,
+		"Tags":            tagsToList(sec.tags, "Key", "Value"),
+	}
+	if sec.description != "" {
+		output["Description"] = sec.description
+	}
+	if sec.kmsKeyID != "" {
+		output["KmsKeyId"] = sec.kmsKeyID
+	}
+	if sec.versionID != "" {
+		output["VersionIdsToStages"] = map[string][]string{sec.versionID: {"AWSCURRENT"}}
+	}
+	if sec.deletedDate != nil {
+		output["DeletedDate"] = epochSeconds(*sec.deletedDate)
+	}
+	return output, nil
+}
+
+func (s *Server) getSecretValue(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	sec, err := s.findSecret(stringValue(input, "SecretId"), false)
+	if err != nil {
+		return nil, err
+	}
+	if sec.versionID == "" {
+		return nil, newError(http.StatusBadRequest, "ResourceNotFoundException", "Secrets Manager can't find the specified secret value for staging label: AWSCURRENT")
+	}
+	return map[string]interface{}{
+		"ARN":           sec.arn,
+		"Name":          sec.name,
+		"SecretString":  sec.secretString,
+		"VersionId":     sec.versionID,
+		"VersionStages": []string{"AWSCURRENT"},
+		"CreatedDate":   epochSeconds(sec.createdDate),
+	}, nil
+}
+
+func (s *Server) putSecretValue(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	sec, err := s.findSecret(stringValue(input, "SecretId"), false)
+	if err != nil {
+		return nil, err
+	}
+	sec.secretString = stringValue(input, "SecretString")
+	sec.versionID = s.versionID(input)
+	return map[string]interface{}{
+		"ARN":           sec.arn,
+		"Name":          sec.name,
+		"VersionId":     sec.versionID,
+		"VersionStages": []string{"AWSCURRENT"},
+	}, nil
+}
+
+func (s *Server) getSecretResourcePolicy(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	sec, err := s.findSecret(stringValue(input, "SecretId"), true)
+	if err != nil {
+		return nil, err
+	}
+	// Resource policies are not supported
+	return map[string]interface{}{"ARN": sec.arn, "Name": sec.name}, nil
+}
+
+func (s *Server) tagSecret(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	sec, err := s.findSecret(stringValue(input, "SecretId"), false)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range tagsFromList(input["Tags"], "Key", "Value") {
+		sec.tags[k] = v
+	}
+	return map[string]interface{}{}, nil
+}
+
+func (s *Server) untagSecret(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	sec, err := s.findSecret(stringValue(input, "SecretId"), false)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range stringsValue(input, "TagKeys") {
+		delete(sec.tags, k)
+	}
+	return map[string]interface{}{}, nil
+}
+
+func (s *Server) deleteSecret(_ *http.Request, input map[string]interface{}) (interface{}, *awsError) {
+	sec, err := s.findSecret(stringValue(input, "SecretId"), true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deletionDate := now
+	if force, _ := input["ForceDeleteWithoutRecovery"].(bool); force {
+		delete(s.secrets, sec.name)
+	} else {
+		// Scheduled deletions are kept, like the recovery window of the real API
+		days := 30.0
+		if v, ok := input["RecoveryWindowInDays"].(float64); ok {
+			days = v
+		}
+		deletionDate = now.Add(time.Duration(days*24) * time.Hour)
+		sec.deletedDate = &now
+	}
+	return map[string]interface{}{
+		"ARN":          sec.arn,
+		"Name":         sec.name,
+		"DeletionDate": epochSeconds(deletionDate),
+	}, nil
+}
+
+// versionID returns the client request token of a secret value, or a generated one
+func (s *Server) versionID(input map[string]interface{}) string {
+	if token := stringValue(input, "ClientRequestToken"); token != "" {
+		return token
+	}
+	id := strings.ToLower(s.nextID("00000000-0000-0000-"))
+	return id[:23] + "-" + id[23:]
+}
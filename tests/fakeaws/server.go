@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package fakeaws is an in-memory stand-in for the subset of the AWS APIs that the
+// ECS modules and their smoke tests call: ECS task definitions and services, IAM
+// roles and policies, Secrets Manager secrets, EFS file systems and STS identity.
+//
+// It lets terraform apply and destroy the smoke tests without network access by
+// pointing the AWS provider's custom endpoints at the server.
+package fakeaws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AccountID is the AWS account that owns every resource of the stand-in
+	AccountID = "123456789012"
+
+	// DefaultRegion is used when the request signature does not carry a region
+	DefaultRegion = "us-east-1"
+)
+
+// Server serves the AWS APIs over HTTP. All resources are kept in memory.
+type Server struct {
+	mu  sync.Mutex
+	ids int
+
+	// ECS
+	taskDefinitions map[string][]*taskDefinition
+	services        map[string]*service
+	ecsTags         map[string]map[string]string
+
+	// IAM
+	roles    map[string]*role
+	policies map[string]*policy
+
+	// Secrets Manager
+	secrets map[string]*secret
+
+	// EFS
+	fileSystems  map[string]*fileSystem
+	accessPoints map[string]*accessPoint
+}
+
+// New returns a server without any resource
+func New() *Server {
+	return &Server{
+		taskDefinitions: map[string][]*taskDefinition{},
+		services:        map[string]*service{},
+		ecsTags:         map[string]map[string]string{},
+		roles:           map[string]*role{},
+		policies:        map[string]*policy{},
+		secrets:         map[string]*secret{},
+		fileSystems:     map[string]*fileSystem{},
+		accessPoints:    map[string]*accessPoint{},
+	}
+}
+
+// ServeHTTP routes a request to the API it targets
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target := r.Header.Get("X-Amz-Target")
+	switch {
+	case strings.HasPrefix(target, ecsTargetPrefix):
+		s.serveECS(w, r, strings.TrimPrefix(target, ecsTargetPrefix))
+	case strings.HasPrefix(target, secretsManagerTargetPrefix):
+		s.serveSecretsManager(w, r, strings.TrimPrefix(target, secretsManagerTargetPrefix))
+	case strings.HasPrefix(r.URL.Path, efsPathPrefix):
+		s.serveEFS(w, r)
+	default:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch r.PostForm.Get("Version") {
+		case iamVersion:
+			s.serveIAM(w, r.PostForm)
+		case stsVersion:
+			s.serveSTS(w, r.PostForm)
+		default:
+			http.Error(w, fmt.Sprintf("unsupported request %s %s", r.Method, r.URL.Path), http.StatusNotImplemented)
+		}
+	}
+}
+
+// nextID returns a unique, upper case identifier with the given prefix
+func (s *Server) nextID(prefix string) string {
+	s.ids++
+	return fmt.Sprintf("%s%017X", prefix, s.ids)
+}
+
+// requestRegion returns the region of the request signature credential scope
+func requestRegion(r *http.Request) string {
+	// Authorization: AWS4-HMAC-SHA256 Credential=AKID/20250101/us-east-1/ecs/aws4_request, ...
+	auth := r.Header.Get("Authorization")
+	i := strings.Index(auth, "Credential=")
+	if i < 0 {
+		return DefaultRegion
+	}
+	scope := strings.Split(strings.SplitN(auth[i+len("Credential="):], ",", 2)[0], "/")
+	if len(scope) < 5 || scope[2] == "" {
+		return DefaultRegion
+	}
+	return scope[2]
+}
+
+// epochSeconds formats a time the way the JSON protocols expect
+func epochSeconds(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
+
+// awsError is an error returned to the client with its AWS error code
+type awsError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *awsError) Error() string {
+	return e.code + ": " + e.message
+}
+
+func newError(status int, code, format string, args ...interface{}) *awsError {
+	return &awsError{status: status, code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// writeJSON writes a JSON protocol response
+func writeJSON(w http.ResponseWriter, contentType string, status int, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// decodeJSON decodes a JSON protocol request body
+func decodeJSON(r *http.Request, v interface{}) *awsError {
+	if r.Body == nil {
+		return nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return newError(http.StatusBadRequest, "SerializationException", "%s", err.Error())
+	}
+	return nil
+}
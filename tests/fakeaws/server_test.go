@@ -0,0 +1,301 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package fakeaws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestConfig starts a server and returns an SDK configuration pointing at it
+func newTestConfig(t *testing.T) (aws.Config, string) {
+	server := httptest.NewServer(New())
+	t.Cleanup(server.Close)
+
+	cfg := aws.Config{
+		Region: DefaultRegion,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		}),
+		BaseEndpoint: aws.String(server.URL),
+	}
+	return cfg, server.URL
+}
+
+func requireErrorCode(t *testing.T, err error, code string) {
+	var apiErr smithy.APIError
+	require.True(t, errors.As(err, &apiErr), "Expected an API error, got %v", err)
+	assert.Equal(t, code, apiErr.ErrorCode())
+}
+
+func TestECSTaskDefinitionLifecycle(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	client := ecs.NewFromConfig(cfg)
+	ctx := context.Background()
+
+	registered, err := client.RegisterTaskDefinition(ctx, &ecs.RegisterTaskDefinitionInput{
+		Family:                  aws.String("terraform-test"),
+		RequiresCompatibilities: []types.Compatibility{types.CompatibilityFargate},
+		NetworkMode:             types.NetworkModeAwsvpc,
+		ContainerDefinitions: []types.ContainerDefinition{{
+			Name:  aws.String("datadog-agent"),
+			Image: aws.String("public.ecr.aws/datadog/agent:latest"),
+			Environment: []types.KeyValuePair{
+				{Name: aws.String("DD_SITE"), Value: aws.String("datadoghq.com")},
+			},
+		}},
+		Tags: []types.Tag{{Key: aws.String("team"), Value: aws.String("containers")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:ecs:us-east-1:123456789012:task-definition/terraform-test:1", aws.ToString(registered.TaskDefinition.TaskDefinitionArn))
+	assert.Contains(t, registered.TaskDefinition.Compatibilities, types.CompatibilityFargate)
+
+	described, err := client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String("terraform-test"),
+		Include:        []types.TaskDefinitionField{types.TaskDefinitionFieldTags},
+	})
+	require.NoError(t, err)
+	require.Len(t, described.TaskDefinition.ContainerDefinitions, 1)
+	assert.Equal(t, "DD_SITE", aws.ToString(described.TaskDefinition.ContainerDefinitions[0].Environment[0].Name))
+	assert.Equal(t, []types.Tag{{Key: aws.String("team"), Value: aws.String("containers")}}, described.Tags)
+
+	_, err = client.DeleteTaskDefinitions(ctx, &ecs.DeleteTaskDefinitionsInput{TaskDefinitions: []string{"terraform-test:1"}})
+	require.NoError(t, err)
+
+	_, err = client.DeregisterTaskDefinition(ctx, &ecs.DeregisterTaskDefinitionInput{TaskDefinition: aws.String("terraform-test:1")})
+	require.NoError(t, err)
+
+	inactive, err := client.ListTaskDefinitions(ctx, &ecs.ListTaskDefinitionsInput{FamilyPrefix: aws.String("terraform-"), Status: types.TaskDefinitionStatusInactive})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arn:aws:ecs:us-east-1:123456789012:task-definition/terraform-test:1"}, inactive.TaskDefinitionArns)
+
+	_, err = client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String("terraform-test")})
+	requireErrorCode(t, err, "ClientException")
+
+	deleted, err := client.DeleteTaskDefinitions(ctx, &ecs.DeleteTaskDefinitionsInput{TaskDefinitions: []string{"terraform-test:1"}})
+	require.NoError(t, err)
+	assert.Len(t, deleted.TaskDefinitions, 1)
+	assert.Empty(t, deleted.Failures)
+}
+
+func TestECSService(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	client := ecs.NewFromConfig(cfg)
+	ctx := context.Background()
+
+	_, err := client.RegisterTaskDefinition(ctx, &ecs.RegisterTaskDefinitionInput{
+		Family:               aws.String("terraform-test"),
+		ContainerDefinitions: []types.ContainerDefinition{{Name: aws.String("app"), Image: aws.String("nginx")}},
+	})
+	require.NoError(t, err)
+
+	_, err = client.CreateService(ctx, &ecs.CreateServiceInput{
+		ServiceName:    aws.String("terraform-test-service"),
+		Cluster:        aws.String("terraform-test-cluster"),
+		TaskDefinition: aws.String("terraform-test"),
+	})
+	require.NoError(t, err)
+
+	clusters, err := client.ListClusters(ctx, &ecs.ListClustersInput{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arn:aws:ecs:us-east-1:123456789012:cluster/terraform-test-cluster"}, clusters.ClusterArns)
+	listed, err := client.ListServices(ctx, &ecs.ListServicesInput{Cluster: aws.String(clusters.ClusterArns[0])})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arn:aws:ecs:us-east-1:123456789012:service/terraform-test-cluster/terraform-test-service"}, listed.ServiceArns)
+
+	described, err := client.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String("terraform-test-cluster"),
+		Services: []string{"terraform-test-service", "missing"},
+	})
+	require.NoError(t, err)
+	require.Len(t, described.Services, 1)
+	assert.Equal(t, "arn:aws:ecs:us-east-1:123456789012:task-definition/terraform-test:1", aws.ToString(described.Services[0].TaskDefinition))
+	require.Len(t, described.Failures, 1)
+	assert.Equal(t, "MISSING", aws.ToString(described.Failures[0].Reason))
+
+	deleted, err := client.DeleteService(ctx, &ecs.DeleteServiceInput{
+		Cluster: aws.String("terraform-test-cluster"),
+		Service: aws.String("terraform-test-service"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "INACTIVE", aws.ToString(deleted.Service.Status))
+
+	listed, err = client.ListServices(ctx, &ecs.ListServicesInput{Cluster: aws.String("terraform-test-cluster")})
+	require.NoError(t, err)
+	assert.Empty(t, listed.ServiceArns)
+}
+
+func TestIAMRolesAndPolicies(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	client := iam.NewFromConfig(cfg)
+	ctx := context.Background()
+
+	trustPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ecs-tasks.amazonaws.com"},"Action":"sts:AssumeRole"}]}`
+	created, err := client.CreateRole(ctx, &iam.CreateRoleInput{
+		RoleName:                 aws.String("terraform-test-role"),
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/terraform-test-role", aws.ToString(created.Role.Arn))
+
+	_, err = client.CreateRole(ctx, &iam.CreateRoleInput{
+		RoleName:                 aws.String("terraform-test-role"),
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+	})
+	requireErrorCode(t, err, "EntityAlreadyExists")
+
+	role, err := client.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String("terraform-test-role")})
+	require.NoError(t, err)
+	document, err := url.QueryUnescape(aws.ToString(role.Role.AssumeRolePolicyDocument))
+	require.NoError(t, err)
+	assert.Equal(t, trustPolicy, document)
+
+	policy, err := client.CreatePolicy(ctx, &iam.CreatePolicyInput{
+		PolicyName:     aws.String("terraform-test-policy"),
+		PolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"ecs:ListClusters","Resource":"*"}]}`),
+	})
+	require.NoError(t, err)
+
+	_, err = client.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{RoleName: aws.String("terraform-test-role"), PolicyArn: policy.Policy.Arn})
+	require.NoError(t, err)
+	_, err = client.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+		RoleName:  aws.String("terraform-test-role"),
+		PolicyArn: aws.String("arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy"),
+	})
+	require.NoError(t, err)
+
+	attached, err := client.ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{RoleName: aws.String("terraform-test-role")})
+	require.NoError(t, err)
+	assert.Len(t, attached.AttachedPolicies, 2)
+
+	roles, err := client.ListRoles(ctx, &iam.ListRolesInput{})
+	require.NoError(t, err)
+	require.Len(t, roles.Roles, 1)
+	assert.Equal(t, "terraform-test-role", aws.ToString(roles.Roles[0].RoleName))
+	policies, err := client.ListPolicies(ctx, &iam.ListPoliciesInput{Scope: iamtypes.PolicyScopeTypeLocal})
+	require.NoError(t, err)
+	require.Len(t, policies.Policies, 1)
+	assert.Equal(t, int32(1), aws.ToInt32(policies.Policies[0].AttachmentCount))
+	entities, err := client.ListEntitiesForPolicy(ctx, &iam.ListEntitiesForPolicyInput{PolicyArn: policy.Policy.Arn})
+	require.NoError(t, err)
+	require.Len(t, entities.PolicyRoles, 1)
+	assert.Equal(t, "terraform-test-role", aws.ToString(entities.PolicyRoles[0].RoleName))
+
+	_, err = client.DeletePolicy(ctx, &iam.DeletePolicyInput{PolicyArn: policy.Policy.Arn})
+	requireErrorCode(t, err, "DeleteConflict")
+	_, err = client.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String("terraform-test-role")})
+	requireErrorCode(t, err, "DeleteConflict")
+
+	for _, p := range attached.AttachedPolicies {
+		_, err = client.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: aws.String("terraform-test-role"), PolicyArn: p.PolicyArn})
+		require.NoError(t, err)
+	}
+	_, err = client.DeletePolicy(ctx, &iam.DeletePolicyInput{PolicyArn: policy.Policy.Arn})
+	require.NoError(t, err)
+	_, err = client.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String("terraform-test-role")})
+	require.NoError(t, err)
+
+	_, err = client.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String("terraform-test-role")})
+	requireErrorCode(t, err, "NoSuchEntity")
+}
+
+func TestSecretsManagerSecrets(t *testing.T) {
+	cfg, _ := newTestConfig(t)
+	client := secretsmanager.NewFromConfig(cfg)
+	ctx := context.Background()
+
+	created, err := client.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+		Name:         aws.String("terraform-test-api-key"),
+		SecretString: aws.String("0123456789abcdef"),
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(aws.ToString(created.ARN), "arn:aws:secretsmanager:us-east-1:123456789012:secret:terraform-test-api-key-"))
+
+	value, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: created.ARN})
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef", aws.ToString(value.SecretString))
+
+	_, err = client.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{SecretId: aws.String("terraform-test-api-key")})
+	require.NoError(t, err)
+
+	_, err = client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: created.ARN})
+	requireErrorCode(t, err, "InvalidRequestException")
+
+	_, err = client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String("missing")})
+	requireErrorCode(t, err, "ResourceNotFoundException")
+}
+
+func TestEFSFileSystemsAndAccessPoints(t *testing.T) {
+	_, endpoint := newTestConfig(t)
+
+	do := func(method, path, body string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(method, endpoint+efsPathPrefix+path, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		output := map[string]interface{}{}
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if len(data) > 0 {
+			require.NoError(t, json.Unmarshal(data, &output))
+		}
+		return resp.StatusCode, output
+	}
+
+	status, fs := do(http.MethodPost, "file-systems", `{"CreationToken":"terraform-test","Tags":[{"Key":"Name","Value":"MyEFSFileSystem"}]}`)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "available", fs["LifeCycleState"])
+	assert.Equal(t, "MyEFSFileSystem", fs["Name"])
+	id := fs["FileSystemId"].(string)
+
+	status, ap := do(http.MethodPost, "access-points", `{"FileSystemId":"`+id+`","RootDirectory":{"Path":"/example"}}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, strings.HasPrefix(ap["AccessPointId"].(string), "fsap-"))
+
+	status, _ = do(http.MethodDelete, "file-systems/"+id, "")
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, _ = do(http.MethodDelete, "access-points/"+ap["AccessPointId"].(string), "")
+	assert.Equal(t, http.StatusNoContent, status)
+	status, _ = do(http.MethodDelete, "file-systems/"+id, "")
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status, output := do(http.MethodGet, "file-systems?FileSystemId="+id, "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "FileSystemNotFound", output["ErrorCode"])
+}
+
+func TestSTSCallerIdentity(t *testing.T) {
+	_, endpoint := newTestConfig(t)
+
+	resp, err := http.PostForm(endpoint, url.Values{"Action": {"GetCallerIdentity"}, "Version": {stsVersion}})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "<Account>"+AccountID+"</Account>")
+}
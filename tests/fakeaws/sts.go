@@ -0,0 +1,36 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package fakeaws
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	stsVersion   = "2011-06-15"
+	stsNamespace = "https://sts.amazonaws.com/doc/2011-06-15/"
+)
+
+// serveSTS answers GetCallerIdentity, which the AWS provider calls on configuration
+func (s *Server) serveSTS(w http.ResponseWriter, input url.Values) {
+	action := input.Get("Action")
+	if action != "GetCallerIdentity" {
+		writeQueryError(w, stsNamespace, newError(http.StatusBadRequest, "InvalidAction", "STS action %s is not supported", action))
+		return
+	}
+
+	writeQueryResponse(w, stsNamespace, action, struct {
+		Account string
+		Arn     string
+		UserId  string
+	}{
+		Account: AccountID,
+		Arn:     fmt.Sprintf("arn:aws:iam::%s:user/terraform-test", AccountID),
+		UserId:  "AIDAFAKEAWSTERRAFORMTEST",
+	})
+}
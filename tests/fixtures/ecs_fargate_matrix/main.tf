@@ -0,0 +1,61 @@
+# Unless explicitly stated otherwise all files in this repository are licensed
+# under the Apache License Version 2.0.
+# This product includes software developed at Datadog (https://www.datadoghq.com/).
+# Copyright 2025-present Datadog, Inc.
+
+################################################################################
+# Task Definition: Feature toggle matrix
+################################################################################
+
+# Planned once per combination of feature toggles by TestFargateToggleMatrix, never applied
+module "dd_task_matrix" {
+  source = "../../../modules/ecs_fargate"
+
+  dd_api_key                       = "test-api-key"
+  dd_site                          = "datadoghq.com"
+  dd_service                       = "matrix-service"
+  dd_is_datadog_dependency_enabled = true
+  dd_readonly_root_filesystem      = var.dd_readonly_root_filesystem
+
+  dd_apm = {
+    enabled        = var.dd_apm_enabled
+    socket_enabled = var.dd_apm_socket_enabled
+  }
+
+  dd_dogstatsd = {
+    enabled        = var.dd_dogstatsd_enabled
+    socket_enabled = var.dd_dogstatsd_socket_enabled
+  }
+
+  dd_log_collection = {
+    enabled = var.dd_log_collection_enabled
+  }
+
+  dd_cws = {
+    enabled = var.dd_cws_enabled
+  }
+
+  family = "terraform-test-matrix"
+  container_definitions = jsonencode([
+    {
+      name       = "entrypoint-app",
+      image      = "ubuntu:22.04",
+      essential  = true,
+      entryPoint = ["/usr/bin/bash", "-c", "sleep infinity"],
+    },
+    {
+      name      = "command-app",
+      image     = "ubuntu:22.04",
+      essential = false,
+      command   = ["sleep", "infinity"],
+    }
+  ])
+  # Smallest task size Fargate runs Windows containers with
+  cpu    = 1024
+  memory = 2048
+  runtime_platform = {
+    cpu_architecture        = var.cpu_architecture
+    operating_system_family = var.operating_system_family
+  }
+  requires_compatibilities = ["FARGATE"]
+}
@@ -0,0 +1,3 @@
+output "task" {
+  value = module.dd_task_matrix
+}
@@ -0,0 +1,10 @@
+provider "aws" {
+  region = "us-east-1"
+
+  # The matrix is only planned, so no AWS API is ever called
+  access_key                  = "terraform-test"
+  secret_key                  = "terraform-test"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+}
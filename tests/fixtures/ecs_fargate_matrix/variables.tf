@@ -0,0 +1,49 @@
+# Unless explicitly stated otherwise all files in this repository are licensed
+# under the Apache License Version 2.0.
+# This product includes software developed at Datadog (https://www.datadoghq.com/).
+# Copyright 2025-present Datadog, Inc.
+
+variable "dd_apm_enabled" {
+  description = "Enable Datadog APM"
+  type        = bool
+}
+
+variable "dd_apm_socket_enabled" {
+  description = "Send traces over the dd-sockets volume"
+  type        = bool
+}
+
+variable "dd_dogstatsd_enabled" {
+  description = "Enable Datadog DogStatsD"
+  type        = bool
+}
+
+variable "dd_dogstatsd_socket_enabled" {
+  description = "Send metrics over the dd-sockets volume"
+  type        = bool
+}
+
+variable "dd_log_collection_enabled" {
+  description = "Enable Datadog log collection"
+  type        = bool
+}
+
+variable "dd_cws_enabled" {
+  description = "Enable Datadog Cloud Workload Security"
+  type        = bool
+}
+
+variable "dd_readonly_root_filesystem" {
+  description = "Run the Datadog Agent with a read-only root filesystem"
+  type        = bool
+}
+
+variable "operating_system_family" {
+  description = "Task runtime platform operating system family"
+  type        = string
+}
+
+variable "cpu_architecture" {
+  description = "Task runtime platform CPU architecture"
+  type        = string
+}
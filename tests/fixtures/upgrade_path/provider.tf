@@ -0,0 +1,24 @@
+locals {
+  # Plan-only and local stand-in test runs never use real AWS credentials
+  placeholder_credentials = var.plan_only || var.aws_endpoint_url != null
+}
+
+provider "aws" {
+  region = "us-east-1"
+
+  # Placeholder credentials are only sent to the local stand-in, plan-only runs make no API call
+  access_key                  = local.placeholder_credentials ? "terraform-test" : null
+  secret_key                  = local.placeholder_credentials ? "terraform-test" : null
+  skip_credentials_validation = var.plan_only
+  skip_metadata_api_check     = local.placeholder_credentials
+  skip_requesting_account_id  = var.plan_only
+
+  # The local stand-in serves every API the smoke tests call
+  endpoints {
+    ecs            = var.aws_endpoint_url
+    efs            = var.aws_endpoint_url
+    iam            = var.aws_endpoint_url
+    secretsmanager = var.aws_endpoint_url
+    sts            = var.aws_endpoint_url
+  }
+}
@@ -0,0 +1,22 @@
+# Unless explicitly stated otherwise all files in this repository are licensed
+# under the Apache License Version 2.0.
+# This product includes software developed at Datadog (https://www.datadoghq.com/).
+# Copyright 2025-present Datadog, Inc.
+
+variable "test_prefix" {
+  description = "The ECS task family name prefix"
+  type        = string
+  default     = "terraform-test"
+}
+
+variable "plan_only" {
+  description = "Configure the AWS provider to plan without credentials or any AWS API call"
+  type        = bool
+  default     = false
+}
+
+variable "aws_endpoint_url" {
+  description = "URL of a local stand-in for the ECS, EFS, IAM, Secrets Manager and STS APIs, used instead of AWS when set"
+  type        = string
+  default     = null
+}
@@ -0,0 +1,233 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/require"
+)
+
+// GoldenTaskDefinition is the part of a rendered task definition stored in the golden files under testdata
+type GoldenTaskDefinition struct {
+	ContainerDefinitions []types.ContainerDefinition `json:"containerDefinitions"`
+	Volumes              []types.Volume              `json:"volumes"`
+}
+
+// goldenIdentityFields identify an element of a list of objects, in order of preference.
+// Fields are named as marshaled by the SDK types, or as in the raw container definitions JSON.
+var goldenIdentityFields = []string{
+	"Name", "name",
+	"SourceVolume", "sourceVolume",
+	"ContainerName", "containerName",
+	"ContainerPort", "containerPort",
+	"Key", "key",
+}
+
+// goldenPath returns the golden file of a smoke test output, by smoke test directory (eg. ecs_fargate)
+func goldenPath(module, scenario string) string {
+	return filepath.Join("testdata", module, scenario+".json")
+}
+
+// NewGoldenTaskDefinition normalizes the containers and volumes of a task definition for a golden file.
+// Values that differ between runs or modes are dropped or replaced: the test prefix, and the EFS volume
+// configurations, which refer to file systems created on apply and are unknown in plan-only mode.
+func NewGoldenTaskDefinition(task TaskDefinitionOutput, testPrefix string) ([]byte, error) {
+	volumes := make([]types.Volume, 0, len(task.Volumes))
+	for _, volume := range task.Volumes {
+		volume.EfsVolumeConfiguration = nil
+		volumes = append(volumes, volume)
+	}
+
+	data, err := json.Marshal(GoldenTaskDefinition{ContainerDefinitions: task.ContainerDefinitions, Volumes: volumes})
+	if err != nil {
+		return nil, err
+	}
+	if testPrefix != defaultTestPrefix {
+		data = bytes.ReplaceAll(data, []byte(testPrefix), []byte(defaultTestPrefix))
+	}
+	return data, nil
+}
+
+// AssertGolden compares a JSON document to the golden file at path, ignoring the order of lists of objects.
+// With update set, the golden file is rewritten instead.
+func AssertGolden(t *testing.T, path string, actual []byte, update bool) {
+	var actualValue interface{}
+	require.NoError(t, json.Unmarshal(actual, &actualValue), "Failed to parse the rendered document")
+	actualValue = canonicalJSON(actualValue)
+
+	if update {
+		data, err := json.MarshalIndent(actualValue, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, append(data, '\n'), 0o644))
+		t.Logf("Updated golden file %s", path)
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("Golden file %s does not exist, run the tests with -update to create it", path)
+	}
+	require.NoError(t, err)
+
+	var expectedValue interface{}
+	require.NoError(t, json.Unmarshal(expected, &expectedValue), "Failed to parse golden file %s", path)
+	expectedValue = canonicalJSON(expectedValue)
+
+	if differences := diffJSON("", expectedValue, actualValue); len(differences) > 0 {
+		t.Errorf("Rendered document does not match golden file %s (run the tests with -update to accept the changes):\n  %s",
+			path, strings.Join(differences, "\n  "))
+	}
+}
+
+// canonicalJSON drops empty values and sorts lists of objects so that equivalent documents are equal.
+// Lists of scalars (eg. a command) keep their order.
+func canonicalJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			element = canonicalJSON(element)
+			if isEmptyJSON(element) {
+				delete(v, key)
+			} else {
+				v[key] = element
+			}
+		}
+		return v
+	case []interface{}:
+		allObjects := len(v) > 0
+		for i, element := range v {
+			v[i] = canonicalJSON(element)
+			if _, ok := v[i].(map[string]interface{}); !ok {
+				allObjects = false
+			}
+		}
+		if allObjects {
+			sort.SliceStable(v, func(i, j int) bool { return jsonString(v[i]) < jsonString(v[j]) })
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isEmptyJSON(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func jsonString(value interface{}) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// diffJSON lists the differences between two canonical documents, one per changed path
+func diffJSON(path string, expected, actual interface{}) []string {
+	expectedMap, expectedIsMap := expected.(map[string]interface{})
+	actualMap, actualIsMap := actual.(map[string]interface{})
+	if expectedIsMap && actualIsMap {
+		return diffJSONObjects(path, expectedMap, actualMap)
+	}
+
+	expectedList, expectedIsList := expected.([]interface{})
+	actualList, actualIsList := actual.([]interface{})
+	if expectedIsList && actualIsList {
+		if expectedIDs, ok := identifyJSONElements(expectedList); ok {
+			if actualIDs, ok := identifyJSONElements(actualList); ok {
+				return diffJSONObjects(path, expectedIDs, actualIDs)
+			}
+		}
+	}
+
+	if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: expected %s, got %s", displayPath(path), jsonString(expected), jsonString(actual))}
+}
+
+func diffJSONObjects(path string, expected, actual map[string]interface{}) []string {
+	keys := make([]string, 0, len(expected)+len(actual))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	for key := range actual {
+		if _, found := expected[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var differences []string
+	for _, key := range keys {
+		elementPath := path + "." + key
+		if strings.HasPrefix(key, "[") {
+			elementPath = path + key
+		}
+
+		expectedElement, inExpected := expected[key]
+		actualElement, inActual := actual[key]
+		switch {
+		case !inActual:
+			differences = append(differences, fmt.Sprintf("%s: removed %s", displayPath(elementPath), jsonString(expectedElement)))
+		case !inExpected:
+			differences = append(differences, fmt.Sprintf("%s: added %s", displayPath(elementPath), jsonString(actualElement)))
+		default:
+			differences = append(differences, diffJSON(elementPath, expectedElement, actualElement)...)
+		}
+	}
+	return differences
+}
+
+// identifyJSONElements keys a list of objects by their identity field (eg. `[Name=DD_SITE]`).
+// It returns false if the list holds anything else or if two elements share an identity.
+func identifyJSONElements(list []interface{}) (map[string]interface{}, bool) {
+	elements := make(map[string]interface{}, len(list))
+	for _, element := range list {
+		object, ok := element.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		id := "[" + jsonString(object) + "]"
+		for _, field := range goldenIdentityFields {
+			if value, found := object[field]; found {
+				id = fmt.Sprintf("[%s=%v]", field, value)
+				break
+			}
+		}
+		if _, duplicate := elements[id]; duplicate {
+			return nil, false
+		}
+		elements[id] = element
+	}
+	return elements, true
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return strings.TrimPrefix(path, ".")
+}
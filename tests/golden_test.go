@@ -0,0 +1,95 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// goldenScenarios lists, by smoke test directory, the outputs with a golden file
+var goldenScenarios = map[string][]string{
+	"ecs_fargate": {
+		"all-dd-disabled",
+		"all-dd-inputs",
+		"all-ecs-inputs",
+		"all-null",
+		"all-windows",
+		"apm-dsd-tcp-udp",
+		"cws-only",
+		"logging-only",
+		"role-parsing-with-path",
+		"role-parsing-without-path",
+		"ust-docker-labels",
+	},
+	"ecs_ec2": {
+		"agent_only",
+		"all_features",
+		"bridge_mode",
+		"host_mode",
+		"socket_only",
+		"tcp_enabled",
+	},
+}
+
+// TestGoldenTaskDefinitions compares the containers and volumes of every scenario to testdata/<smoke test>
+func (s *ecsSuite[T]) TestGoldenTaskDefinitions() {
+	log.Println("TestGoldenTaskDefinitions: Running test...")
+
+	for _, scenario := range goldenScenarios[s.smokeTest] {
+		s.Run(scenario, func() {
+			task := s.taskOutput(scenario)
+			golden, err := NewGoldenTaskDefinition(task.Definition(), s.testPrefix)
+			s.Require().NoError(err, "Failed to render the golden document")
+			AssertGolden(s.T(), goldenPath(s.smokeTest, scenario), golden, *updateGolden)
+		})
+	}
+}
+
+// TestGoldenDiff tests that golden comparisons ignore ordering and empty values, and report changes by path
+func TestGoldenDiff(t *testing.T) {
+	expected := canonicalJSON(map[string]interface{}{
+		"containerDefinitions": []interface{}{
+			map[string]interface{}{
+				"Name":        "datadog-agent",
+				"Command":     []interface{}{"sh", "-c"},
+				"Environment": []interface{}{map[string]interface{}{"Name": "DD_SITE", "Value": "datadoghq.com"}, map[string]interface{}{"Name": "DD_ENV", "Value": "prod"}},
+			},
+			map[string]interface{}{"Name": "app", "Secrets": []interface{}{}},
+		},
+	})
+
+	reordered := canonicalJSON(map[string]interface{}{
+		"containerDefinitions": []interface{}{
+			map[string]interface{}{"Name": "app", "Links": nil},
+			map[string]interface{}{
+				"Name":        "datadog-agent",
+				"Command":     []interface{}{"sh", "-c"},
+				"Environment": []interface{}{map[string]interface{}{"Name": "DD_ENV", "Value": "prod"}, map[string]interface{}{"Name": "DD_SITE", "Value": "datadoghq.com"}},
+			},
+		},
+	})
+	assert.Empty(t, diffJSON("", expected, reordered))
+
+	changed := canonicalJSON(map[string]interface{}{
+		"containerDefinitions": []interface{}{
+			map[string]interface{}{
+				"Name":        "datadog-agent",
+				"Command":     []interface{}{"-c", "sh"},
+				"Environment": []interface{}{map[string]interface{}{"Name": "DD_SITE", "Value": "datadoghq.eu"}, map[string]interface{}{"Name": "DD_VERSION", "Value": "1.0"}},
+			},
+		},
+	})
+	assert.Equal(t, []string{
+		`containerDefinitions[Name=app]: removed {"Name":"app"}`,
+		`containerDefinitions[Name=datadog-agent].Command: expected ["sh","-c"], got ["-c","sh"]`,
+		`containerDefinitions[Name=datadog-agent].Environment[Name=DD_ENV]: removed {"Name":"DD_ENV","Value":"prod"}`,
+		`containerDefinitions[Name=datadog-agent].Environment[Name=DD_SITE].Value: expected "datadoghq.com", got "datadoghq.eu"`,
+		`containerDefinitions[Name=datadog-agent].Environment[Name=DD_VERSION]: added {"Name":"DD_VERSION","Value":"1.0"}`,
+	}, diffJSON("", expected, changed))
+}
@@ -0,0 +1,233 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	iamTestSecretArn        = "arn:aws:secretsmanager:us-east-1:123456789012:secret:dd-api-key"
+	iamTestExecutionRoleArn = "arn:aws:iam::123456789012:role/ecs/existing-exec-role"
+	iamTestTaskRoleArn      = "arn:aws:iam::123456789012:role/existing-task-role"
+)
+
+// iamTaskPermissions are the actions granted on every resource by the dd_ecs_task_permissions policy of each module
+var iamTaskPermissions = map[string][]string{
+	"ecs_fargate": {"ecs:DescribeContainerInstances", "ecs:ListClusters", "ecs:ListContainerInstances"},
+	"ecs_ec2": {
+		"ec2:DescribeInstances", "ec2:DescribeTags",
+		"ecs:DescribeContainerInstances", "ecs:DescribeTasks", "ecs:ListClusters", "ecs:ListContainerInstances", "ecs:ListTasks",
+	},
+}
+
+// iamCase is a combination of the inputs deciding which IAM resources the modules create or edit
+type iamCase struct {
+	executionRole       bool
+	taskRole            bool
+	secret              bool
+	addDdECSPermissions bool
+}
+
+func (c iamCase) String() string {
+	return fmt.Sprintf("execution_role=%t/task_role=%t/secret=%t/add_dd_ecs_permissions=%t", c.executionRole, c.taskRole, c.secret, c.addDdECSPermissions)
+}
+
+// iamCases lists every combination of given roles and API key secret. add_dd_ecs_permissions is only
+// varied when a role is given, since it is set on the roles and changes nothing otherwise.
+func iamCases() []iamCase {
+	var cases []iamCase
+	for _, executionRole := range []bool{false, true} {
+		for _, taskRole := range []bool{false, true} {
+			for _, secret := range []bool{false, true} {
+				c := iamCase{executionRole: executionRole, taskRole: taskRole, secret: secret, addDdECSPermissions: true}
+				cases = append(cases, c)
+				if executionRole || taskRole {
+					c.addDdECSPermissions = false
+					cases = append(cases, c)
+				}
+			}
+		}
+	}
+	return cases
+}
+
+// vars returns the IAM inputs of the case. add_dd_ecs_permissions only applies to roles given to the module.
+func (c iamCase) vars() map[string]interface{} {
+	vars := map[string]interface{}{"dd_api_key": "test-api-key", "dd_api_key_secret": nil}
+	if c.secret {
+		vars["dd_api_key"] = nil
+		vars["dd_api_key_secret"] = map[string]interface{}{"arn": iamTestSecretArn}
+	}
+	if c.executionRole {
+		vars["execution_role"] = map[string]interface{}{"arn": iamTestExecutionRoleArn, "add_dd_ecs_permissions": c.addDdECSPermissions}
+	}
+	if c.taskRole {
+		vars["task_role"] = map[string]interface{}{"arn": iamTestTaskRoleArn, "add_dd_ecs_permissions": c.addDdECSPermissions}
+	}
+	return vars
+}
+
+// expectedResources lists the addresses of the IAM resources the modules should plan, by resource type
+func (c iamCase) expectedResources() map[string][]string {
+	secretPermissions := c.secret && (!c.executionRole || c.addDdECSPermissions)
+	editTaskRole := c.taskRole && c.addDdECSPermissions
+
+	expected := map[string][]string{
+		"aws_iam_role":                   {},
+		"aws_iam_policy":                 {},
+		"aws_iam_role_policy_attachment": {},
+	}
+	add := func(resourceType string, addresses ...string) {
+		expected[resourceType] = append(expected[resourceType], addresses...)
+	}
+
+	if secretPermissions {
+		add("aws_iam_policy", "aws_iam_policy.dd_secret_access[0]")
+		if c.executionRole {
+			add("aws_iam_role_policy_attachment", "aws_iam_role_policy_attachment.existing_role_dd_secret[0]")
+		} else {
+			add("aws_iam_role", "aws_iam_role.new_ecs_task_execution_role[0]")
+			add("aws_iam_role_policy_attachment",
+				`aws_iam_role_policy_attachment.new_ecs_task_execution_role_policy["AmazonECSTaskExecutionRolePolicy"]`,
+				`aws_iam_role_policy_attachment.new_ecs_task_execution_role_policy["DDSecretAccess"]`)
+		}
+	}
+
+	if !c.taskRole || editTaskRole {
+		add("aws_iam_policy", "aws_iam_policy.dd_ecs_task_permissions[0]")
+	}
+	if editTaskRole {
+		add("aws_iam_role_policy_attachment", "aws_iam_role_policy_attachment.existing_role_ecs_task_permissions[0]")
+	}
+	if !c.taskRole {
+		add("aws_iam_role", "aws_iam_role.new_ecs_task_role[0]")
+		add("aws_iam_role_policy_attachment", `aws_iam_role_policy_attachment.new_role_ecs_task_permissions["DDECSTaskPermissions"]`)
+	}
+	return expected
+}
+
+// TestIAMPermissionMatrix plans both modules for every combination of given execution and task roles,
+// API key secret and add_dd_ecs_permissions, and checks exactly which IAM resources are planned and what their policies grant
+func TestIAMPermissionMatrix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping the IAM permission matrix in short mode")
+	}
+
+	for _, module := range []string{"ecs_fargate", "ecs_ec2"} {
+		t.Run(module, func(t *testing.T) {
+			dir := initModuleAsRoot(t, module)
+
+			for _, c := range iamCases() {
+				t.Run(c.String(), func(t *testing.T) {
+					vars := c.vars()
+					for name, value := range moduleBaseVars[module] {
+						if _, overridden := vars[name]; !overridden {
+							vars[name] = value
+						}
+					}
+					assertIAMResources(t, module, c, planModuleAsRoot(t, dir, vars))
+				})
+			}
+		})
+	}
+}
+
+// assertIAMResources checks the IAM resources planned for a case, their targets and the permissions they grant
+func assertIAMResources(t *testing.T, module string, c iamCase, plan *terraform.PlanStruct) {
+	for resourceType, expected := range c.expectedResources() {
+		assert.ElementsMatch(t, expected, ResourceAddresses(PlannedResources(plan, resourceType, ""), ""), "Unexpected %s resources", resourceType)
+	}
+
+	policies := PlannedResources(plan, "aws_iam_policy", "")
+	if policy, found := policies["aws_iam_policy.dd_secret_access[0]"]; found {
+		grants := policyGrants(t, policy)
+		assert.Equal(t, map[string][]string{iamTestSecretArn: {"secretsmanager:GetSecretValue"}}, grants, "Secret policy should only grant reading the API key secret")
+	}
+	if policy, found := policies["aws_iam_policy.dd_ecs_task_permissions[0]"]; found {
+		grants := policyGrants(t, policy)
+		assert.Equal(t, map[string][]string{"*": iamTaskPermissions[module]}, grants, "Unexpected task role permissions")
+	}
+
+	// Existing roles are edited by name, which is the last element of their ARN path
+	attachments := PlannedResources(plan, "aws_iam_role_policy_attachment", "")
+	if attachment, found := attachments["aws_iam_role_policy_attachment.existing_role_dd_secret[0]"]; found {
+		assert.Equal(t, "existing-exec-role", ResourceAttribute(attachment, "role"), "Secret policy should be attached to the given execution role")
+	}
+	if attachment, found := attachments["aws_iam_role_policy_attachment.existing_role_ecs_task_permissions[0]"]; found {
+		assert.Equal(t, "existing-task-role", ResourceAttribute(attachment, "role"), "Task permissions should be attached to the given task role")
+	}
+	if attachment, found := attachments[`aws_iam_role_policy_attachment.new_ecs_task_execution_role_policy["AmazonECSTaskExecutionRolePolicy"]`]; found {
+		assert.Equal(t, "arn:aws:iam::aws:policy/service-role/AmazonECSTaskExecutionRolePolicy", ResourceAttribute(attachment, "policy_arn"))
+	}
+
+	// Given roles are used as is by the task definition, created ones are only known after apply
+	taskDefinitions := PlannedResources(plan, "aws_ecs_task_definition", "")
+	require.Len(t, taskDefinitions, 1, "A single task definition should be planned")
+	var taskDefinition *tfjson.StateResource
+	for _, resource := range taskDefinitions {
+		taskDefinition = resource
+	}
+	if c.executionRole {
+		assert.Equal(t, iamTestExecutionRoleArn, ResourceAttribute(taskDefinition, "execution_role_arn"))
+	}
+	if c.taskRole {
+		assert.Equal(t, iamTestTaskRoleArn, ResourceAttribute(taskDefinition, "task_role_arn"))
+	}
+}
+
+func policyGrants(t *testing.T, policy *tfjson.StateResource) map[string][]string {
+	document, err := DecodeIAMPolicyDocument(ResourceAttribute(policy, "policy"))
+	require.NoError(t, err, "Failed to parse policy %s", policy.Address)
+	return document.Grants()
+}
+
+// TestStateResources tests the lookup of resources by type and module in a `terraform show -json` state
+func TestStateResources(t *testing.T) {
+	state := &tfjson.State{Values: &tfjson.StateValues{RootModule: &tfjson.StateModule{
+		ChildModules: []*tfjson.StateModule{
+			{
+				Address: "module.dd_task",
+				Resources: []*tfjson.StateResource{
+					{Address: "module.dd_task.aws_iam_role.new_ecs_task_role[0]", Mode: tfjson.ManagedResourceMode, Type: "aws_iam_role"},
+					{Address: "module.dd_task.aws_iam_policy.dd_ecs_task_permissions[0]", Mode: tfjson.ManagedResourceMode, Type: "aws_iam_policy"},
+					{Address: "module.dd_task.data.aws_iam_policy_document.dd_ecs_task_permissions[0]", Mode: tfjson.DataResourceMode, Type: "aws_iam_policy"},
+				},
+			},
+			{
+				Address:   "module.other",
+				Resources: []*tfjson.StateResource{{Address: "module.other.aws_iam_role.new_ecs_task_role[0]", Mode: tfjson.ManagedResourceMode, Type: "aws_iam_role"}},
+			},
+		},
+	}}}
+
+	assert.Equal(t, []string{"aws_iam_role.new_ecs_task_role[0]"}, ResourceAddresses(StateResources(state, "aws_iam_role", "module.dd_task."), "module.dd_task."))
+	assert.Len(t, StateResources(state, "aws_iam_role", ""), 2)
+	assert.Len(t, StateResources(state, "aws_iam_policy", ""), 1)
+}
+
+// TestDecodeIAMPolicyDocument tests policies with actions and resources given as strings or lists
+func TestDecodeIAMPolicyDocument(t *testing.T) {
+	document, err := DecodeIAMPolicyDocument(`{
+		"Version": "2012-10-17",
+		"Statement": [
+			{"Effect": "Allow", "Action": "secretsmanager:GetSecretValue", "Resource": "arn:secret"},
+			{"Effect": "Allow", "Action": ["ecs:ListTasks", "ecs:ListClusters"], "Resource": ["*"]},
+			{"Effect": "Deny", "Action": "ecs:DeleteCluster", "Resource": "*"}
+		]
+	}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"arn:secret": {"secretsmanager:GetSecretValue"},
+		"*":          {"ecs:ListClusters", "ecs:ListTasks"},
+	}, document.Grants())
+}
@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// PlannedChanges plans the root module against its applied state and describes every resource it would change.
+// It returns nothing when the plan is empty, ie. when applying again is a no-op.
+func PlannedChanges(t *testing.T, options *terraform.Options) []string {
+	planOptions := *options
+	planOptions.PlanFilePath = filepath.Join(t.TempDir(), "plan.out")
+	terraform.Plan(t, &planOptions)
+	plan := terraform.ShowWithStruct(t, &planOptions)
+	return describeResourceChanges(plan.RawPlan.ResourceChanges)
+}
+
+// describeResourceChanges describes the resource changes of a plan, with the attributes they update
+func describeResourceChanges(changes []*tfjson.ResourceChange) []string {
+	var descriptions []string
+	for _, change := range changes {
+		if change.Change == nil || change.Change.Actions.NoOp() || change.Change.Actions.Read() {
+			continue
+		}
+
+		var actions []string
+		for _, action := range change.Change.Actions {
+			actions = append(actions, string(action))
+		}
+		description := fmt.Sprintf("%s: %s", change.Address, strings.Join(actions, ", "))
+
+		if differences := diffResourceAttributes(change.Change.Before, change.Change.After); len(differences) > 0 {
+			description += "\n    " + strings.Join(differences, "\n    ")
+		}
+		descriptions = append(descriptions, description)
+	}
+	sort.Strings(descriptions)
+	return descriptions
+}
+
+// diffResourceAttributes lists the attribute differences between two resource values.
+// Attributes holding a JSON document (eg. container_definitions) are compared as documents.
+func diffResourceAttributes(before, after interface{}) []string {
+	beforeAttributes, _ := before.(map[string]interface{})
+	afterAttributes, _ := after.(map[string]interface{})
+	if beforeAttributes == nil || afterAttributes == nil {
+		return nil
+	}
+	return diffJSON("", decodeJSONAttributes(beforeAttributes), decodeJSONAttributes(afterAttributes))
+}
+
+func decodeJSONAttributes(attributes map[string]interface{}) map[string]interface{} {
+	decoded := make(map[string]interface{}, len(attributes))
+	for name, value := range attributes {
+		decoded[name] = value
+		if text, ok := value.(string); ok && (strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{")) {
+			var document interface{}
+			if json.Unmarshal([]byte(text), &document) == nil {
+				decoded[name] = document
+			}
+		}
+	}
+	return decoded
+}
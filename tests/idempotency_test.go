@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"log"
+	"strings"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNoChangesAfterApply tests that planning again after apply finds nothing to change
+func (s *ecsSuite[T]) TestNoChangesAfterApply() {
+	log.Println("TestNoChangesAfterApply: Running test...")
+	if s.planOnly {
+		s.T().Skip("Nothing is applied in plan-only mode")
+	}
+
+	for _, workspace := range s.workspaces {
+		changes := PlannedChanges(s.T(), workspace.Options)
+		s.Empty(changes, "Planning %s again after apply should not change anything:\n%s", workspace.Scenario, strings.Join(changes, "\n"))
+	}
+}
+
+// TestDescribeResourceChanges tests that only actual changes are reported, with their container definition differences
+func TestDescribeResourceChanges(t *testing.T) {
+	changes := []*tfjson.ResourceChange{
+		{
+			Address: "module.dd_task.data.aws_iam_policy_document.dd_ecs_task_permissions[0]",
+			Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionRead}},
+		},
+		{
+			Address: "module.dd_task.aws_iam_role.new_ecs_task_role[0]",
+			Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}},
+		},
+		{
+			Address: "module.dd_task.aws_ecs_task_definition.this",
+			Change: &tfjson.Change{
+				Actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate},
+				Before: map[string]interface{}{
+					"family":                "terraform-test-all-null",
+					"container_definitions": `[{"name":"datadog-agent","memory":256}]`,
+				},
+				After: map[string]interface{}{
+					"family":                "terraform-test-all-null",
+					"container_definitions": `[{"name":"datadog-agent","memory_limit_mib":256,"systemControls":[]}]`,
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"module.dd_task.aws_ecs_task_definition.this: delete, create\n" +
+			"    container_definitions[name=datadog-agent].memory: removed 256\n" +
+			"    container_definitions[name=datadog-agent].memory_limit_mib: added 256\n" +
+			"    container_definitions[name=datadog-agent].systemControls: added []",
+	}, describeResourceChanges(changes))
+}
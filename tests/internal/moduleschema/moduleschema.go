@@ -0,0 +1,128 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+// Package moduleschema reads the variables of the modules from their HCL, for the tools comparing
+// them to the smoke tests or to each other
+package moduleschema
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ElementPath is appended to the path of a list, set or map to name the attributes of its elements
+const ElementPath = "[*]"
+
+// Variable is a variable declared by a module
+type Variable struct {
+	Name string
+	// Type is the type constraint of the variable, cty.DynamicPseudoType when it has none
+	Type cty.Type
+	// Block is the variable block, eg. to tell its own references from the module's
+	Block *hclsyntax.Block
+}
+
+// ParseFile parses a .tf file
+func ParseFile(path string) (*hclsyntax.Body, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return file.Body.(*hclsyntax.Body), nil
+}
+
+// ParseModule parses the .tf files of a module directory, keyed by file name
+func ParseModule(dir string) (map[string]*hclsyntax.Body, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	bodies := map[string]*hclsyntax.Body{}
+	for _, path := range paths {
+		body, err := ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		bodies[filepath.Base(path)] = body
+	}
+	return bodies, nil
+}
+
+// Variables returns the variables declared in the .tf files of a module directory, sorted by name
+func Variables(dir string) ([]Variable, error) {
+	bodies, err := ParseModule(dir)
+	if err != nil {
+		return nil, err
+	}
+	var variables []Variable
+	for _, body := range bodies {
+		for _, block := range body.Blocks {
+			if block.Type != "variable" || len(block.Labels) != 1 {
+				continue
+			}
+			variable := Variable{Name: block.Labels[0], Type: cty.DynamicPseudoType, Block: block}
+			if attribute, found := block.Body.Attributes["type"]; found {
+				var diags hcl.Diagnostics
+				variable.Type, _, diags = typeexpr.TypeConstraintWithDefaults(attribute.Expr)
+				if diags.HasErrors() {
+					return nil, fmt.Errorf("type of variable %s: %w", variable.Name, diags)
+				}
+			}
+			variables = append(variables, variable)
+		}
+	}
+	slices.SortFunc(variables, func(a, b Variable) int { return strings.Compare(a.Name, b.Name) })
+	return variables, nil
+}
+
+// Paths returns the path of a value of type typ and those of the attributes nested in it, sorted.
+// Attributes of list, set and map elements are under ElementPath, eg. volumes[*].name.
+func Paths(path string, typ cty.Type) []string {
+	paths := []string{path}
+	switch {
+	case typ.IsObjectType():
+		for name, attributeType := range typ.AttributeTypes() {
+			paths = append(paths, Paths(path+"."+name, attributeType)...)
+		}
+	case typ.IsListType(), typ.IsSetType(), typ.IsMapType():
+		if element := typ.ElementType(); !element.IsPrimitiveType() && element != cty.DynamicPseudoType {
+			paths = append(paths, Paths(path+ElementPath, element)...)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// ReadAllowlist returns the entries of an allowlist file, one per line. Text after a # is a comment.
+func ReadAllowlist(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	allowed := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			allowed[line] = true
+		}
+	}
+	return allowed, scanner.Err()
+}
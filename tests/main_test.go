@@ -6,60 +6,156 @@
 package test
 
 import (
+	"errors"
+	"flag"
 	"log"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/suite"
 )
 
-// ECSFargateSuite defines the test suite for ECS Fargate
-type ECSFargateSuite struct {
+// updateGolden regenerates the golden files under testdata instead of comparing against them
+var updateGolden = flag.Bool("update", false, "regenerate the golden files under testdata")
+
+// moduleTaskOutput is the decoded output of the ecs_fargate or ecs_ec2 module
+type moduleTaskOutput interface {
+	Definition() TaskDefinitionOutput
+	RegisterInput() *ecs.RegisterTaskDefinitionInput
+}
+
+// ecsSuite sets up the scenarios of a smoke test directory and holds the tests run on every module.
+// Each module suite embeds it and adds the tests of its own scenarios.
+type ecsSuite[T moduleTaskOutput] struct {
 	suite.Suite
+	// smokeTest is the directory under smoke_tests, named after the module it tests
+	smokeTest string
+	// vars are the variables of the smoke tests specific to the module
+	vars map[string]interface{}
+	// terraformBinary overrides the binary terratest picks when set
+	terraformBinary string
+	decode          func([]byte) (T, error)
+
 	terraformOptions *terraform.Options
 	testPrefix       string
+	planOnly         bool
+	outputs          *OutputCache[T]
+	fakeAWS          *httptest.Server
+	workspaces       []*ScenarioWorkspace
+	stopSignals      func()
+	// testTasks are the outputs read by the running test, reported by TearDownTest when it fails.
+	// testify runs the tests of a suite one at a time, so they need no lock.
+	testTasks map[string]TaskDefinitionOutput
+}
+
+// ECSFargateSuite defines the test suite for ECS Fargate
+type ECSFargateSuite struct {
+	ecsSuite[FargateTaskOutput]
 }
 
 // TODO: Separate tests into different package for each tf module
 // TestECSFargateSuite is the entry point for the test suite
 func TestECSFargateSuite(t *testing.T) {
-	suite.Run(t, new(ECSFargateSuite))
+	suite.Run(t, &ECSFargateSuite{ecsSuite[FargateTaskOutput]{
+		smokeTest: "ecs_fargate",
+		vars:      map[string]interface{}{"dd_service": "test-service"},
+		decode:    DecodeFargateTaskOutput,
+	}})
 }
 
 // SetupSuite is run once at the beginning of the test suite
-func (s *ECSFargateSuite) SetupSuite() {
-	log.Println("Setting up test suite resources...")
+func (s *ecsSuite[T]) SetupSuite() {
+	log.Printf("Setting up %s test suite resources...", s.smokeTest)
+	s.planOnly = IsPlanOnly()
 
 	// All resources must be prefixed with terraform-test
-	s.testPrefix = "terraform-test"
+	s.testPrefix = defaultTestPrefix
 	ciJobID := os.Getenv("CI_JOB_ID")
 	if ciJobID != "" {
 		s.testPrefix = s.testPrefix + "-" + ciJobID
 	}
 
+	// Variables to pass to the Terraform module
+	vars := map[string]interface{}{
+		"dd_api_key":  "test-api-key",
+		"dd_site":     "datadoghq.com",
+		"test_prefix": s.testPrefix,
+		"plan_only":   s.planOnly,
+	}
+	for name, value := range s.vars {
+		vars[name] = value
+	}
+
 	// Define the Terraform options for the suite
 	s.terraformOptions = &terraform.Options{
 		// Path to the smoke_tests directory
-		TerraformDir: "../smoke_tests/ecs_fargate",
-		// Variables to pass to the Terraform module
-		Vars: map[string]interface{}{
-			"dd_api_key":  "test-api-key",
-			"dd_service":  "test-service",
-			"dd_site":     "datadoghq.com",
-			"test_prefix": s.testPrefix,
-		},
+		TerraformDir:    "../smoke_tests/" + s.smokeTest,
+		TerraformBinary: s.terraformBinary,
+		Vars:            vars,
 		RetryableTerraformErrors: map[string]string{
-			"couldn't find resource": "terratest could not find the resource. check for access denied errors in cloudtrial",
+			"couldn't find resource": "terratest could not find the resource. check for access denied errors in cloudtrail",
 		},
 	}
 
-	// Run terraform init and apply
-	terraform.InitAndApply(s.T(), s.terraformOptions)
+	// Only render the task definitions when no AWS resources should be created
+	if s.planOnly {
+		log.Println("Running in plan-only mode, no resources will be created...")
+	} else if IsFakeAWS() {
+		// Serve the AWS APIs locally instead of creating real resources
+		log.Println("Running against the local AWS stand-in...")
+		s.fakeAWS = StartFakeAWS(s.terraformOptions)
+	}
+
+	// Apply, or plan, each scenario in its own workspace, then read all their outputs once
+	s.workspaces = NewScenarioWorkspaces(s.T(), s.terraformOptions)
+	// Resources being applied must not leak when the run is interrupted before TearDownSuite
+	if !s.planOnly && os.Getenv("SKIP_teardown") == "" {
+		s.stopSignals = DestroyOnSignal(s.workspaces)
+	}
+	outputs, errs := SetupScenarios(s.T(), s.workspaces, s.terraformOptions.TerraformDir, s.planOnly)
+	s.outputs = NewOutputCache(outputs, errs, s.decode)
 }
 
 // TearDownSuite is run once at the end of the test suite
-func (s *ECSFargateSuite) TearDownSuite() {
-	log.Println("Tearing down test suite resources...")
-	terraform.Destroy(s.T(), s.terraformOptions)
+func (s *ecsSuite[T]) TearDownSuite() {
+	log.Printf("Tearing down %s test suite resources...", s.smokeTest)
+	TeardownScenarios(s.T(), s.workspaces, s.planOnly)
+	if s.stopSignals != nil {
+		s.stopSignals()
+	}
+	if s.fakeAWS != nil {
+		s.fakeAWS.Close()
+	}
+}
+
+// SetupTest is run before each test
+func (s *ecsSuite[T]) SetupTest() {
+	if os.Getenv("SKIP_validate") != "" {
+		s.T().Skip("SKIP_validate is set")
+	}
+	s.testTasks = map[string]TaskDefinitionOutput{}
+}
+
+// TearDownTest is run after each test, reporting every container change of the outputs read by a failed test
+func (s *ecsSuite[T]) TearDownTest() {
+	if !s.T().Failed() {
+		return
+	}
+	for key, task := range s.testTasks {
+		LogContainerDiffFromGolden(s.T(), goldenPath(s.smokeTest, key), task, s.testPrefix)
+	}
+}
+
+// taskOutput returns a module output read by SetupSuite from the applied state, or from the plan in plan-only mode
+func (s *ecsSuite[T]) taskOutput(key string) T {
+	task, err := s.outputs.Get(key)
+	if errors.Is(err, ErrOutputNotFound) && ScenariosSelected() {
+		s.T().Skipf("Output %s is not in the scenarios selected by %s", key, ScenariosEnvVar)
+	}
+	s.Require().NoError(err, "Failed to read the %s output", key)
+	s.testTasks[key] = task.Definition()
+	return task
 }
@@ -0,0 +1,296 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// FargateToggles is a combination of the ecs_fargate feature toggles planned by the matrix test
+type FargateToggles struct {
+	APM                    bool
+	APMSocket              bool
+	Dogstatsd              bool
+	DogstatsdSocket        bool
+	LogCollection          bool
+	CWS                    bool
+	ReadonlyRootFilesystem bool
+	Platform               types.RuntimePlatform
+}
+
+// fargateMatrixPlatforms are the runtime platforms combined with the toggles, the first one being the default
+var fargateMatrixPlatforms = []types.RuntimePlatform{
+	{OperatingSystemFamily: types.OSFamilyLinux, CpuArchitecture: types.CPUArchitectureX8664},
+	{OperatingSystemFamily: types.OSFamilyLinux, CpuArchitecture: types.CPUArchitectureArm64},
+	{OperatingSystemFamily: types.OSFamilyWindowsServer2022Core, CpuArchitecture: types.CPUArchitectureX8664},
+}
+
+// fargateMatrixAppContainers are the application containers of the matrix fixture, by whether they have an entryPoint
+var fargateMatrixAppContainers = map[string]bool{
+	"entrypoint-app": true,
+	"command-app":    false,
+}
+
+// cwsEntryPointPrefix is prepended by the module to the entryPoint of application containers traced by CWS
+var cwsEntryPointPrefix = []string{"/cws-instrumentation-volume/cws-instrumentation", "trace", "--"}
+
+// IsLinux reports whether the toggles target a Linux runtime platform
+func (c FargateToggles) IsLinux() bool {
+	return c.Platform.OperatingSystemFamily == types.OSFamilyLinux
+}
+
+// Valid reports whether the module preconditions accept the toggles
+func (c FargateToggles) Valid() bool {
+	return c.IsLinux() || (!c.LogCollection && !c.ReadonlyRootFilesystem)
+}
+
+// Vars returns the variables of the matrix fixture for the toggles
+func (c FargateToggles) Vars() map[string]interface{} {
+	return map[string]interface{}{
+		"dd_apm_enabled":              c.APM,
+		"dd_apm_socket_enabled":       c.APMSocket,
+		"dd_dogstatsd_enabled":        c.Dogstatsd,
+		"dd_dogstatsd_socket_enabled": c.DogstatsdSocket,
+		"dd_log_collection_enabled":   c.LogCollection,
+		"dd_cws_enabled":              c.CWS,
+		"dd_readonly_root_filesystem": c.ReadonlyRootFilesystem,
+		"operating_system_family":     string(c.Platform.OperatingSystemFamily),
+		"cpu_architecture":            string(c.Platform.CpuArchitecture),
+	}
+}
+
+// String formats the toggles as the fixture variables, to reproduce a combination with `terraform plan`
+func (c FargateToggles) String() string {
+	vars := c.Vars()
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		args = append(args, fmt.Sprintf("-var %s=%v", name, vars[name]))
+	}
+	return strings.Join(args, " ")
+}
+
+// AllFargateToggles enumerates every combination of toggles accepted by the module preconditions
+func AllFargateToggles() []FargateToggles {
+	var combinations []FargateToggles
+	for _, platform := range fargateMatrixPlatforms {
+		for bits := 0; bits < 1<<7; bits++ {
+			toggles := FargateToggles{
+				APM:                    bits&(1<<0) != 0,
+				APMSocket:              bits&(1<<1) != 0,
+				Dogstatsd:              bits&(1<<2) != 0,
+				DogstatsdSocket:        bits&(1<<3) != 0,
+				LogCollection:          bits&(1<<4) != 0,
+				CWS:                    bits&(1<<5) != 0,
+				ReadonlyRootFilesystem: bits&(1<<6) != 0,
+				Platform:               platform,
+			}
+			if toggles.Valid() {
+				combinations = append(combinations, toggles)
+			}
+		}
+	}
+	return combinations
+}
+
+// SampleFargateToggles returns n distinct combinations picked at random, or all of them if there are fewer
+func SampleFargateToggles(rng *rand.Rand, n int) []FargateToggles {
+	combinations := AllFargateToggles()
+	rng.Shuffle(len(combinations), func(i, j int) { combinations[i], combinations[j] = combinations[j], combinations[i] })
+	if n < len(combinations) {
+		combinations = combinations[:n]
+	}
+	return combinations
+}
+
+// ShrinkFargateToggles returns a minimal combination that still fails, by disabling one toggle or
+// restoring the default platform at a time for as long as the combination keeps failing
+func ShrinkFargateToggles(failing FargateToggles, fails func(FargateToggles) bool) FargateToggles {
+	for {
+		shrunk := false
+		for _, candidate := range shrinkCandidates(failing) {
+			if candidate.Valid() && fails(candidate) {
+				failing = candidate
+				shrunk = true
+				break
+			}
+		}
+		if !shrunk {
+			return failing
+		}
+	}
+}
+
+// shrinkCandidates lists the combinations one step simpler than c
+func shrinkCandidates(c FargateToggles) []FargateToggles {
+	var candidates []FargateToggles
+	for _, toggle := range []*bool{&c.APM, &c.APMSocket, &c.Dogstatsd, &c.DogstatsdSocket, &c.LogCollection, &c.CWS, &c.ReadonlyRootFilesystem} {
+		if *toggle {
+			*toggle = false
+			candidates = append(candidates, c)
+			*toggle = true
+		}
+	}
+	if c.Platform != fargateMatrixPlatforms[0] {
+		candidate := c
+		candidate.Platform = fargateMatrixPlatforms[0]
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// FargateInvariant is a property every task definition rendered by the ecs_fargate module must satisfy
+type FargateInvariant struct {
+	Name  string
+	Check func(toggles FargateToggles, task FargateTaskOutput) error
+}
+
+// FargateInvariants are the documented properties checked for every combination of toggles
+var FargateInvariants = []FargateInvariant{
+	{"task definition rules", checkTaskDefinitionRules},
+	{"ECS accepts the RegisterTaskDefinition input", checkRegisterInput},
+	{"dd-sockets volume exists iff an app container mounts /var/run/datadog", checkSocketVolumeMounted},
+	{"dd-sockets volume exists iff a socket is enabled on Linux", checkSocketVolumeToggles},
+	{"socket and host environment variables follow the APM and DogStatsD toggles", checkSocketEnvVars},
+	{"CWS entryPoint prefix only on containers with an entryPoint", checkCWSEntryPoint},
+	{"log router and awsfirelens log driver exist iff log collection is enabled", checkLogRouter},
+	{"read-only root filesystem is applied to the agent with its init-volume container", checkReadonlyRootFilesystem},
+}
+
+// CheckFargateInvariants returns the failure of every invariant the task violates, by invariant name
+func CheckFargateInvariants(toggles FargateToggles, task FargateTaskOutput) map[string]error {
+	failures := map[string]error{}
+	for _, invariant := range FargateInvariants {
+		if err := invariant.Check(toggles, task); err != nil {
+			failures[invariant.Name] = err
+		}
+	}
+	return failures
+}
+
+func checkTaskDefinitionRules(_ FargateToggles, task FargateTaskOutput) error {
+	if violations := ValidateTaskDefinition(task.TaskDefinitionOutput); len(violations) > 0 {
+		return fmt.Errorf("%v", violations)
+	}
+	return nil
+}
+
+func checkRegisterInput(_ FargateToggles, task FargateTaskOutput) error {
+	if violations := ValidateRegisterInput(task.RegisterInput()); len(violations) > 0 {
+		return fmt.Errorf("%v", violations)
+	}
+	return nil
+}
+
+func checkSocketVolumeMounted(_ FargateToggles, task FargateTaskOutput) error {
+	_, hasVolume := ecsassert.GetVolume(task.Volumes, "dd-sockets")
+	mounted := false
+	for name := range fargateMatrixAppContainers {
+		container, found := ecsassert.GetContainer(task.ContainerDefinitions, name)
+		if !found {
+			return fmt.Errorf("container %s not found", name)
+		}
+		for _, mountPoint := range container.MountPoints {
+			if aws.ToString(mountPoint.ContainerPath) == "/var/run/datadog" {
+				mounted = true
+			}
+		}
+	}
+	if hasVolume != mounted {
+		return fmt.Errorf("dd-sockets volume present: %t, /var/run/datadog mounted by an app container: %t", hasVolume, mounted)
+	}
+	return nil
+}
+
+func checkSocketVolumeToggles(toggles FargateToggles, task FargateTaskOutput) error {
+	_, hasVolume := ecsassert.GetVolume(task.Volumes, "dd-sockets")
+	expected := toggles.IsLinux() && ((toggles.APM && toggles.APMSocket) || (toggles.Dogstatsd && toggles.DogstatsdSocket))
+	if hasVolume != expected {
+		return fmt.Errorf("dd-sockets volume present: %t, expected: %t", hasVolume, expected)
+	}
+	return nil
+}
+
+func checkSocketEnvVars(toggles FargateToggles, task FargateTaskOutput) error {
+	dsdSocket := toggles.IsLinux() && toggles.Dogstatsd && toggles.DogstatsdSocket
+	expected := map[string]bool{
+		"DD_TRACE_AGENT_URL": toggles.IsLinux() && toggles.APM && toggles.APMSocket,
+		"DD_DOGSTATSD_URL":   dsdSocket,
+		"DD_AGENT_HOST":      toggles.Dogstatsd && !dsdSocket,
+	}
+	for name := range fargateMatrixAppContainers {
+		container, found := ecsassert.GetContainer(task.ContainerDefinitions, name)
+		if !found {
+			return fmt.Errorf("container %s not found", name)
+		}
+		for envName, want := range expected {
+			if _, set := ecsassert.GetEnvVar(container, envName); set != want {
+				return fmt.Errorf("container %s: %s set: %t, expected: %t", name, envName, set, want)
+			}
+		}
+	}
+	return nil
+}
+
+func checkCWSEntryPoint(toggles FargateToggles, task FargateTaskOutput) error {
+	traced := toggles.IsLinux() && toggles.CWS
+	for name, hasEntryPoint := range fargateMatrixAppContainers {
+		container, found := ecsassert.GetContainer(task.ContainerDefinitions, name)
+		if !found {
+			return fmt.Errorf("container %s not found", name)
+		}
+		prefixed := len(container.EntryPoint) > len(cwsEntryPointPrefix) && slices.Equal(container.EntryPoint[:len(cwsEntryPointPrefix)], cwsEntryPointPrefix)
+		if !hasEntryPoint && len(container.EntryPoint) > 0 {
+			return fmt.Errorf("container %s has no entryPoint but was given %v", name, container.EntryPoint)
+		}
+		if hasEntryPoint && prefixed != traced {
+			return fmt.Errorf("container %s entryPoint prefixed by CWS: %t, expected: %t", name, prefixed, traced)
+		}
+	}
+	return nil
+}
+
+func checkLogRouter(toggles FargateToggles, task FargateTaskOutput) error {
+	_, hasRouter := ecsassert.GetContainer(task.ContainerDefinitions, "datadog-log-router")
+	if hasRouter != toggles.LogCollection {
+		return fmt.Errorf("datadog-log-router present: %t, expected: %t", hasRouter, toggles.LogCollection)
+	}
+	for name := range fargateMatrixAppContainers {
+		container, _ := ecsassert.GetContainer(task.ContainerDefinitions, name)
+		firelens := container.LogConfiguration != nil && container.LogConfiguration.LogDriver == types.LogDriverAwsfirelens
+		if firelens != toggles.LogCollection {
+			return fmt.Errorf("container %s logs to awsfirelens: %t, expected: %t", name, firelens, toggles.LogCollection)
+		}
+	}
+	return nil
+}
+
+func checkReadonlyRootFilesystem(toggles FargateToggles, task FargateTaskOutput) error {
+	agent, found := ecsassert.GetContainer(task.ContainerDefinitions, "datadog-agent")
+	if !found {
+		return fmt.Errorf("container datadog-agent not found")
+	}
+	if readonly := aws.ToBool(agent.ReadonlyRootFilesystem); readonly != toggles.ReadonlyRootFilesystem {
+		return fmt.Errorf("datadog-agent readonlyRootFilesystem: %t, expected: %t", readonly, toggles.ReadonlyRootFilesystem)
+	}
+	if _, hasInit := ecsassert.GetContainer(task.ContainerDefinitions, "init-volume"); hasInit != toggles.ReadonlyRootFilesystem {
+		return fmt.Errorf("init-volume present: %t, expected: %t", hasInit, toggles.ReadonlyRootFilesystem)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Environment variables tuning TestFargateToggleMatrix
+const (
+	// MatrixSamplesEnvVar is the number of combinations to plan, or `all` to plan every combination (default 16)
+	MatrixSamplesEnvVar = "TERRAFORM_MATRIX_SAMPLES"
+	// MatrixSeedEnvVar seeds the sampling to reproduce a run, or `random` to seed it from the current time
+	// (default: a fixed seed, or a random one when MatrixSamplesEnvVar is set)
+	MatrixSeedEnvVar = "TERRAFORM_MATRIX_SEED"
+)
+
+const (
+	defaultMatrixSamples = 16
+	defaultMatrixSeed    = 1
+)
+
+// TestFargateToggleMatrix plans the ecs_fargate module for combinations of its feature toggles and checks
+// the invariants of every rendered task definition. Failing combinations are shrunk to a minimal reproducer.
+func TestFargateToggleMatrix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping the toggle matrix in short mode")
+	}
+
+	combinations := fargateMatrixCombinations(t)
+	options := &terraform.Options{
+		TerraformDir: copyMatrixFixture(t),
+		NoColor:      true,
+	}
+	terraform.Init(t, options)
+
+	for _, toggles := range combinations {
+		failures := planFargateInvariants(t, options, toggles)
+		if len(failures) == 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(failures))
+		for name := range failures {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		// Shrink on the first failed invariant so the reproducer shows a single problem
+		first := names[0]
+		minimal := ShrinkFargateToggles(toggles, func(candidate FargateToggles) bool {
+			_, fails := planFargateInvariants(t, options, candidate)[first]
+			return fails
+		})
+		t.Errorf("Combination violates %d invariant(s):\n  %s\nFirst failure %q: %v\nMinimal reproducer: terraform plan %s",
+			len(failures), toggles, first, failures[first], minimal)
+	}
+}
+
+// fargateMatrixCombinations picks the combinations to plan from the environment
+func fargateMatrixCombinations(t *testing.T) []FargateToggles {
+	samples := os.Getenv(MatrixSamplesEnvVar)
+	if samples == "all" {
+		return AllFargateToggles()
+	}
+
+	n := defaultMatrixSamples
+	if samples != "" {
+		var err error
+		n, err = strconv.Atoi(samples)
+		if err != nil {
+			t.Fatalf("Invalid %s: %v", MatrixSamplesEnvVar, err)
+		}
+	}
+
+	// Keep the default run deterministic, only explore new combinations when asked to
+	var seed int64 = defaultMatrixSeed
+	switch value := os.Getenv(MatrixSeedEnvVar); {
+	case value == "random", value == "" && samples != "":
+		seed = time.Now().UnixNano()
+	case value != "":
+		var err error
+		seed, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			t.Fatalf("Invalid %s: %v", MatrixSeedEnvVar, err)
+		}
+	}
+	t.Logf("Sampling %d combinations with %s=%d", n, MatrixSeedEnvVar, seed)
+	return SampleFargateToggles(rand.New(rand.NewSource(seed)), n)
+}
+
+// planFargateInvariants plans the matrix fixture for the toggles and returns the failed invariants.
+// A plan error is reported as a failure of the `plan` pseudo-invariant.
+func planFargateInvariants(t *testing.T, options *terraform.Options, toggles FargateToggles) map[string]error {
+	planOptions := &terraform.Options{
+		TerraformDir: options.TerraformDir,
+		NoColor:      true,
+		Vars:         toggles.Vars(),
+		PlanFilePath: filepath.Join(t.TempDir(), "plan.out"),
+		Logger:       logger.Discard,
+	}
+
+	if _, err := terraform.PlanE(t, planOptions); err != nil {
+		return map[string]error{"plan": err}
+	}
+	plan, err := terraform.ShowWithStructE(t, planOptions)
+	if err != nil {
+		return map[string]error{"plan": err}
+	}
+	output, err := plannedOutputJsonE(plan, "task")
+	if err != nil {
+		return map[string]error{"plan": err}
+	}
+	task, err := DecodeFargateTaskOutput([]byte(output))
+	if err != nil {
+		return map[string]error{"plan": fmt.Errorf("decoding the planned task definition: %w", err)}
+	}
+	return CheckFargateInvariants(toggles, task)
+}
+
+// TestAllFargateToggles tests that the enumeration skips the combinations rejected by the module preconditions
+func TestAllFargateToggles(t *testing.T) {
+	combinations := AllFargateToggles()
+	// 128 combinations per Linux platform, 32 on Windows without log collection nor read-only root filesystem
+	assert.Len(t, combinations, 2*128+32)
+
+	seen := map[string]bool{}
+	for _, toggles := range combinations {
+		assert.True(t, toggles.Valid(), "Invalid combination enumerated: %s", toggles)
+		assert.False(t, seen[toggles.String()], "Duplicate combination enumerated: %s", toggles)
+		seen[toggles.String()] = true
+	}
+
+	assert.Len(t, SampleFargateToggles(rand.New(rand.NewSource(1)), 10), 10)
+}
+
+// TestShrinkFargateToggles tests that shrinking keeps only the toggles needed to fail
+func TestShrinkFargateToggles(t *testing.T) {
+	failing := FargateToggles{
+		APM:                    true,
+		APMSocket:              true,
+		Dogstatsd:              true,
+		LogCollection:          true,
+		CWS:                    true,
+		ReadonlyRootFilesystem: true,
+		Platform:               fargateMatrixPlatforms[1],
+	}
+	// Fails whenever CWS and the read-only root filesystem are both enabled
+	fails := func(c FargateToggles) bool { return c.CWS && c.ReadonlyRootFilesystem }
+
+	assert.Equal(t, FargateToggles{
+		CWS:                    true,
+		ReadonlyRootFilesystem: true,
+		Platform:               fargateMatrixPlatforms[0],
+	}, ShrinkFargateToggles(failing, fails))
+}
+
+// copyMatrixFixture copies the matrix fixture and the modules to a temporary directory, so terraform init
+// leaves no working files in the repository. The fixture keeps its place next to the modules it sources.
+func copyMatrixFixture(t *testing.T) string {
+	root := t.TempDir()
+	fixture := filepath.Join(root, "tests", "fixtures", "ecs_fargate_matrix")
+	for source, destination := range map[string]string{
+		filepath.Join("..", "modules"):                  filepath.Join(root, "modules"),
+		filepath.Join("fixtures", "ecs_fargate_matrix"): fixture,
+	} {
+		require.NoError(t, os.MkdirAll(destination, 0o755))
+		require.NoError(t, files.CopyFolderContentsWithFilter(source, destination, isTerraformSource))
+	}
+	return fixture
+}
@@ -0,0 +1,367 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// TaskDefinitionOutput holds the task definition attributes output by both the ecs_fargate and ecs_ec2 modules
+type TaskDefinitionOutput struct {
+	Arn                     string
+	ArnWithoutRevision      string
+	Revision                int32
+	Family                  string
+	ContainerDefinitions    []types.ContainerDefinition
+	NetworkMode             types.NetworkMode
+	PidMode                 types.PidMode
+	IpcMode                 types.IpcMode
+	ExecutionRoleArn        string
+	TaskRoleArn             string
+	RequiresCompatibilities []types.Compatibility
+	PlacementConstraints    []types.TaskDefinitionPlacementConstraint
+	ProxyConfiguration      *types.ProxyConfiguration
+	Volumes                 []types.Volume
+	Tags                    map[string]string
+	TagsAll                 map[string]string
+	SkipDestroy             bool
+	TrackLatest             bool
+}
+
+// FargateTaskOutput is the decoded output of the ecs_fargate module
+type FargateTaskOutput struct {
+	TaskDefinitionOutput
+	Cpu                  string
+	Memory               string
+	EnableFaultInjection bool
+	EphemeralStorage     *types.EphemeralStorage
+	RuntimePlatform      *types.RuntimePlatform
+}
+
+// EC2TaskOutput is the decoded output of the ecs_ec2 module
+type EC2TaskOutput struct {
+	TaskDefinitionOutput
+	ServiceID                 string
+	ServiceName               string
+	ServiceCluster            string
+	ServiceDesiredCount       *int32
+	DogstatsdEnvVars          []types.KeyValuePair
+	APMEnvVars                []types.KeyValuePair
+	ProfilingEnvVars          []types.KeyValuePair
+	TraceInferredProxyEnvVars []types.KeyValuePair
+	DataStreamsEnvVars        []types.KeyValuePair
+	AppDdSocketsMount         []types.MountPoint
+	AppDdSocketsVolume        []types.Volume
+}
+
+// Definition returns the task definition attributes output by both modules, so checks can run on either output
+func (task TaskDefinitionOutput) Definition() TaskDefinitionOutput {
+	return task
+}
+
+// tfTaskDefinition mirrors the aws_ecs_task_definition attributes as terraform renders them in JSON:
+// snake_case keys and nested blocks as lists
+type tfTaskDefinition struct {
+	Arn                     string            `json:"arn"`
+	ArnWithoutRevision      string            `json:"arn_without_revision"`
+	Revision                int32             `json:"revision"`
+	Family                  string            `json:"family"`
+	ContainerDefinitions    string            `json:"container_definitions"`
+	Cpu                     string            `json:"cpu"`
+	Memory                  string            `json:"memory"`
+	NetworkMode             string            `json:"network_mode"`
+	PidMode                 string            `json:"pid_mode"`
+	IpcMode                 string            `json:"ipc_mode"`
+	ExecutionRoleArn        string            `json:"execution_role_arn"`
+	TaskRoleArn             string            `json:"task_role_arn"`
+	RequiresCompatibilities []string          `json:"requires_compatibilities"`
+	EnableFaultInjection    bool              `json:"enable_fault_injection"`
+	SkipDestroy             bool              `json:"skip_destroy"`
+	TrackLatest             bool              `json:"track_latest"`
+	Tags                    map[string]string `json:"tags"`
+	TagsAll                 map[string]string `json:"tags_all"`
+	EphemeralStorage        []struct {
+		SizeInGib int32 `json:"size_in_gib"`
+	} `json:"ephemeral_storage"`
+	PlacementConstraints []struct {
+		Expression string `json:"expression"`
+		Type       string `json:"type"`
+	} `json:"placement_constraints"`
+	ProxyConfiguration []struct {
+		ContainerName string            `json:"container_name"`
+		Properties    map[string]string `json:"properties"`
+		Type          string            `json:"type"`
+	} `json:"proxy_configuration"`
+	RuntimePlatform []struct {
+		CpuArchitecture       string `json:"cpu_architecture"`
+		OperatingSystemFamily string `json:"operating_system_family"`
+	} `json:"runtime_platform"`
+	Volume []tfVolume `json:"volume"`
+}
+
+// tfVolume mirrors a volume block of aws_ecs_task_definition
+type tfVolume struct {
+	Name                      string `json:"name"`
+	HostPath                  string `json:"host_path"`
+	DockerVolumeConfiguration []struct {
+		Autoprovision *bool             `json:"autoprovision"`
+		Driver        string            `json:"driver"`
+		DriverOpts    map[string]string `json:"driver_opts"`
+		Labels        map[string]string `json:"labels"`
+		Scope         string            `json:"scope"`
+	} `json:"docker_volume_configuration"`
+	EfsVolumeConfiguration []struct {
+		FileSystemId          string `json:"file_system_id"`
+		RootDirectory         string `json:"root_directory"`
+		TransitEncryption     string `json:"transit_encryption"`
+		TransitEncryptionPort int32  `json:"transit_encryption_port"`
+		AuthorizationConfig   []struct {
+			AccessPointId string `json:"access_point_id"`
+			Iam           string `json:"iam"`
+		} `json:"authorization_config"`
+	} `json:"efs_volume_configuration"`
+	FsxWindowsFileServerVolumeConfiguration []struct {
+		FileSystemId        string `json:"file_system_id"`
+		RootDirectory       string `json:"root_directory"`
+		AuthorizationConfig []struct {
+			CredentialsParameter string `json:"credentials_parameter"`
+			Domain               string `json:"domain"`
+		} `json:"authorization_config"`
+	} `json:"fsx_windows_file_server_volume_configuration"`
+}
+
+// tfEC2Outputs mirrors the outputs of the ecs_ec2 module that are not task definition attributes
+type tfEC2Outputs struct {
+	ServiceID                 *string              `json:"service_id"`
+	ServiceName               *string              `json:"service_name"`
+	ServiceCluster            *string              `json:"service_cluster"`
+	ServiceDesiredCount       *int32               `json:"service_desired_count"`
+	DogstatsdEnvVars          []types.KeyValuePair `json:"dogstatsd_env_vars"`
+	APMEnvVars                []types.KeyValuePair `json:"apm_env_vars"`
+	ProfilingEnvVars          []types.KeyValuePair `json:"profiling_env_vars"`
+	TraceInferredProxyEnvVars []types.KeyValuePair `json:"trace_inferred_proxy_env_vars"`
+	DataStreamsEnvVars        []types.KeyValuePair `json:"data_streams_env_vars"`
+	AppDdSocketsMount         []types.MountPoint   `json:"app_dd_sockets_mount"`
+	AppDdSocketsVolume        []tfVolume           `json:"app_dd_sockets_volume"`
+}
+
+// DecodeFargateTaskOutput decodes the JSON output of an ecs_fargate module (eg. from terraform.OutputJson)
+func DecodeFargateTaskOutput(data []byte) (FargateTaskOutput, error) {
+	var raw tfTaskDefinition
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return FargateTaskOutput{}, fmt.Errorf("decoding ecs_fargate output: %w", err)
+	}
+
+	common, err := raw.taskDefinitionOutput()
+	if err != nil {
+		return FargateTaskOutput{}, err
+	}
+	output := FargateTaskOutput{
+		TaskDefinitionOutput: common,
+		Cpu:                  raw.Cpu,
+		Memory:               raw.Memory,
+		EnableFaultInjection: raw.EnableFaultInjection,
+	}
+	if len(raw.EphemeralStorage) > 0 {
+		output.EphemeralStorage = &types.EphemeralStorage{SizeInGiB: raw.EphemeralStorage[0].SizeInGib}
+	}
+	if len(raw.RuntimePlatform) > 0 {
+		output.RuntimePlatform = &types.RuntimePlatform{
+			CpuArchitecture:       types.CPUArchitecture(raw.RuntimePlatform[0].CpuArchitecture),
+			OperatingSystemFamily: types.OSFamily(raw.RuntimePlatform[0].OperatingSystemFamily),
+		}
+	}
+	return output, nil
+}
+
+// DecodeEC2TaskOutput decodes the JSON output of an ecs_ec2 module (eg. from terraform.OutputJson)
+func DecodeEC2TaskOutput(data []byte) (EC2TaskOutput, error) {
+	var raw tfTaskDefinition
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return EC2TaskOutput{}, fmt.Errorf("decoding ecs_ec2 output: %w", err)
+	}
+	var rawOutputs tfEC2Outputs
+	if err := json.Unmarshal(data, &rawOutputs); err != nil {
+		return EC2TaskOutput{}, fmt.Errorf("decoding ecs_ec2 output: %w", err)
+	}
+
+	common, err := raw.taskDefinitionOutput()
+	if err != nil {
+		return EC2TaskOutput{}, err
+	}
+	return EC2TaskOutput{
+		TaskDefinitionOutput:      common,
+		ServiceID:                 aws.ToString(rawOutputs.ServiceID),
+		ServiceName:               aws.ToString(rawOutputs.ServiceName),
+		ServiceCluster:            aws.ToString(rawOutputs.ServiceCluster),
+		ServiceDesiredCount:       rawOutputs.ServiceDesiredCount,
+		DogstatsdEnvVars:          rawOutputs.DogstatsdEnvVars,
+		APMEnvVars:                rawOutputs.APMEnvVars,
+		ProfilingEnvVars:          rawOutputs.ProfilingEnvVars,
+		TraceInferredProxyEnvVars: rawOutputs.TraceInferredProxyEnvVars,
+		DataStreamsEnvVars:        rawOutputs.DataStreamsEnvVars,
+		AppDdSocketsMount:         rawOutputs.AppDdSocketsMount,
+		AppDdSocketsVolume:        convertVolumes(rawOutputs.AppDdSocketsVolume),
+	}, nil
+}
+
+// taskDefinitionOutput converts the attributes shared by both modules to their ECS API types
+func (raw tfTaskDefinition) taskDefinitionOutput() (TaskDefinitionOutput, error) {
+	output := TaskDefinitionOutput{
+		Arn:                raw.Arn,
+		ArnWithoutRevision: raw.ArnWithoutRevision,
+		Revision:           raw.Revision,
+		Family:             raw.Family,
+		NetworkMode:        types.NetworkMode(raw.NetworkMode),
+		PidMode:            types.PidMode(raw.PidMode),
+		IpcMode:            types.IpcMode(raw.IpcMode),
+		ExecutionRoleArn:   raw.ExecutionRoleArn,
+		TaskRoleArn:        raw.TaskRoleArn,
+		Volumes:            convertVolumes(raw.Volume),
+		Tags:               raw.Tags,
+		TagsAll:            raw.TagsAll,
+		SkipDestroy:        raw.SkipDestroy,
+		TrackLatest:        raw.TrackLatest,
+	}
+
+	if raw.ContainerDefinitions != "" {
+		containers, err := DecodeContainerDefinitions([]byte(raw.ContainerDefinitions))
+		if err != nil {
+			return TaskDefinitionOutput{}, err
+		}
+		output.ContainerDefinitions = containers
+	}
+
+	for _, compatibility := range raw.RequiresCompatibilities {
+		output.RequiresCompatibilities = append(output.RequiresCompatibilities, types.Compatibility(compatibility))
+	}
+
+	for _, constraint := range raw.PlacementConstraints {
+		output.PlacementConstraints = append(output.PlacementConstraints, types.TaskDefinitionPlacementConstraint{
+			Expression: optionalString(constraint.Expression),
+			Type:       types.TaskDefinitionPlacementConstraintType(constraint.Type),
+		})
+	}
+
+	if len(raw.ProxyConfiguration) > 0 {
+		proxy := raw.ProxyConfiguration[0]
+		output.ProxyConfiguration = &types.ProxyConfiguration{
+			ContainerName: aws.String(proxy.ContainerName),
+			Type:          types.ProxyConfigurationType(proxy.Type),
+			Properties:    keyValuePairs(proxy.Properties),
+		}
+	}
+	return output, nil
+}
+
+// DecodeContainerDefinitions decodes a container_definitions document, rejecting any key that is not a field
+// of the ECS ContainerDefinition: ECS silently drops unknown keys, so the setting they carry would have no effect
+func DecodeContainerDefinitions(data []byte) ([]types.ContainerDefinition, error) {
+	var documents []json.RawMessage
+	if err := json.Unmarshal(data, &documents); err != nil {
+		return nil, fmt.Errorf("decoding container definitions: %w", err)
+	}
+
+	containers := make([]types.ContainerDefinition, 0, len(documents))
+	for i, document := range documents {
+		decoder := json.NewDecoder(bytes.NewReader(document))
+		decoder.DisallowUnknownFields()
+		var container types.ContainerDefinition
+		if err := decoder.Decode(&container); err != nil {
+			var named struct{ Name string }
+			_ = json.Unmarshal(document, &named)
+			return nil, fmt.Errorf("decoding container definition %d (%s): %w", i, named.Name, err)
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// convertVolumes converts terraform volume blocks to ECS API volumes
+func convertVolumes(volumes []tfVolume) []types.Volume {
+	var result []types.Volume
+	for _, v := range volumes {
+		volume := types.Volume{Name: aws.String(v.Name)}
+		if v.HostPath != "" {
+			volume.Host = &types.HostVolumeProperties{SourcePath: aws.String(v.HostPath)}
+		}
+
+		if len(v.DockerVolumeConfiguration) > 0 {
+			docker := v.DockerVolumeConfiguration[0]
+			volume.DockerVolumeConfiguration = &types.DockerVolumeConfiguration{
+				Autoprovision: docker.Autoprovision,
+				Driver:        optionalString(docker.Driver),
+				DriverOpts:    docker.DriverOpts,
+				Labels:        docker.Labels,
+				Scope:         types.Scope(docker.Scope),
+			}
+		}
+
+		if len(v.EfsVolumeConfiguration) > 0 {
+			efs := v.EfsVolumeConfiguration[0]
+			volume.EfsVolumeConfiguration = &types.EFSVolumeConfiguration{
+				FileSystemId:      aws.String(efs.FileSystemId),
+				RootDirectory:     optionalString(efs.RootDirectory),
+				TransitEncryption: types.EFSTransitEncryption(efs.TransitEncryption),
+			}
+			if efs.TransitEncryptionPort != 0 {
+				volume.EfsVolumeConfiguration.TransitEncryptionPort = aws.Int32(efs.TransitEncryptionPort)
+			}
+			if len(efs.AuthorizationConfig) > 0 {
+				volume.EfsVolumeConfiguration.AuthorizationConfig = &types.EFSAuthorizationConfig{
+					AccessPointId: optionalString(efs.AuthorizationConfig[0].AccessPointId),
+					Iam:           types.EFSAuthorizationConfigIAM(efs.AuthorizationConfig[0].Iam),
+				}
+			}
+		}
+
+		if len(v.FsxWindowsFileServerVolumeConfiguration) > 0 {
+			fsx := v.FsxWindowsFileServerVolumeConfiguration[0]
+			volume.FsxWindowsFileServerVolumeConfiguration = &types.FSxWindowsFileServerVolumeConfiguration{
+				FileSystemId:  aws.String(fsx.FileSystemId),
+				RootDirectory: aws.String(fsx.RootDirectory),
+			}
+			if len(fsx.AuthorizationConfig) > 0 {
+				volume.FsxWindowsFileServerVolumeConfiguration.AuthorizationConfig = &types.FSxWindowsFileServerAuthorizationConfig{
+					CredentialsParameter: aws.String(fsx.AuthorizationConfig[0].CredentialsParameter),
+					Domain:               aws.String(fsx.AuthorizationConfig[0].Domain),
+				}
+			}
+		}
+
+		result = append(result, volume)
+	}
+	return result
+}
+
+// keyValuePairs converts a map to key-value pairs sorted by name
+func keyValuePairs(values map[string]string) []types.KeyValuePair {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]types.KeyValuePair, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, types.KeyValuePair{Name: aws.String(name), Value: aws.String(values[name])})
+	}
+	return pairs
+}
+
+// optionalString returns nil for the empty strings terraform renders for unset attributes
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// ErrOutputNotFound is returned by OutputCache.Get for an output the cache does not hold
+var ErrOutputNotFound = errors.New("output not found")
+
+// OutputCache holds every root output of a suite, read once, and decodes each of them on first use.
+// Reading an output never runs terraform. The cache is safe for concurrent use.
+type OutputCache[T any] struct {
+	decode  func([]byte) (T, error)
+	raw     map[string][]byte
+	errs    map[string]error
+	mu      sync.Mutex
+	decoded map[string]T
+}
+
+// NewOutputCache returns a cache of the given JSON outputs, decoded with decode.
+// errs holds the outputs that could not be read, returned by Get instead of a value.
+func NewOutputCache[T any](raw map[string][]byte, errs map[string]error, decode func([]byte) (T, error)) *OutputCache[T] {
+	return &OutputCache[T]{decode: decode, raw: raw, errs: errs, decoded: map[string]T{}}
+}
+
+// appliedOutputs returns the JSON value of every output of the applied root module
+func appliedOutputs(t *testing.T, options *terraform.Options) map[string][]byte {
+	raw := map[string][]byte{}
+	for key, value := range terraform.OutputAll(t, options) {
+		data, err := json.Marshal(value)
+		require.NoError(t, err, "Failed to encode output %s", key)
+		raw[key] = data
+	}
+	return raw
+}
+
+// plannedOutputs returns PlannedOutputJson for every root output of the plan, and the error of those it cannot render
+func plannedOutputs(plan *terraform.PlanStruct) (map[string][]byte, map[string]error) {
+	raw := map[string][]byte{}
+	errs := map[string]error{}
+	if plan.RawPlan.Config != nil && plan.RawPlan.Config.RootModule != nil {
+		for key := range plan.RawPlan.Config.RootModule.Outputs {
+			output, err := plannedOutputJsonE(plan, key)
+			if err != nil {
+				errs[key] = err
+				continue
+			}
+			raw[key] = []byte(output)
+		}
+	}
+	return raw, errs
+}
+
+// Keys returns the names of all the outputs, sorted
+func (c *OutputCache[T]) Keys() []string {
+	keys := make([]string, 0, len(c.raw)+len(c.errs))
+	for key := range c.raw {
+		keys = append(keys, key)
+	}
+	for key := range c.errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Raw returns the JSON value of the output, undecoded
+func (c *OutputCache[T]) Raw(key string) ([]byte, error) {
+	if err, found := c.errs[key]; found {
+		return nil, err
+	}
+	data, found := c.raw[key]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrOutputNotFound, key)
+	}
+	return data, nil
+}
+
+// Get returns the decoded output. Decoded values are shared between callers, which must not modify them.
+func (c *OutputCache[T]) Get(key string) (T, error) {
+	var zero T
+	if err, found := c.errs[key]; found {
+		return zero, err
+	}
+	data, found := c.raw[key]
+	if !found {
+		return zero, fmt.Errorf("%w: %s", ErrOutputNotFound, key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value, found := c.decoded[key]; found {
+		return value, nil
+	}
+	value, err := c.decode(data)
+	if err != nil {
+		return zero, fmt.Errorf("decoding output %s: %w", key, err)
+	}
+	c.decoded[key] = value
+	return value, nil
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOutputCache tests that concurrent reads decode each output once, and that unreadable outputs report their error
+func TestOutputCache(t *testing.T) {
+	var decodes atomic.Int32
+	cache := NewOutputCache(
+		map[string][]byte{
+			"all-dd-inputs": []byte(`{"family": "terraform-test-all-dd-inputs", "cpu": "256"}`),
+			"broken":        []byte(`{"family": 1}`),
+		},
+		map[string]error{"not-a-module": errors.New("output not-a-module does not refer to a module")},
+		func(data []byte) (FargateTaskOutput, error) {
+			decodes.Add(1)
+			return DecodeFargateTaskOutput(data)
+		},
+	)
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task, err := cache.Get("all-dd-inputs")
+			assert.NoError(t, err)
+			assert.Equal(t, "terraform-test-all-dd-inputs", task.Family)
+			assert.Equal(t, "256", task.Cpu)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), decodes.Load(), "The output should be decoded once")
+
+	assert.Equal(t, []string{"all-dd-inputs", "broken", "not-a-module"}, cache.Keys())
+
+	_, err := cache.Get("broken")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "decoding output broken")
+
+	_, err = cache.Get("not-a-module")
+	assert.EqualError(t, err, "output not-a-module does not refer to a module")
+
+	_, err = cache.Get("missing")
+	assert.ErrorIs(t, err, ErrOutputNotFound)
+
+	raw, err := cache.Raw("broken")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"family": 1}`, string(raw))
+	_, err = cache.Raw("not-a-module")
+	assert.EqualError(t, err, "output not-a-module does not refer to a module")
+}
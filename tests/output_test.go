@@ -0,0 +1,130 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeFargateTaskOutput tests the decoding of a module output as rendered by `terraform output -json`
+func TestDecodeFargateTaskOutput(t *testing.T) {
+	output := `{
+		"arn": "arn:aws:ecs:us-east-1:123456789012:task-definition/terraform-test-all-ecs-inputs:3",
+		"revision": 3,
+		"family": "terraform-test-all-ecs-inputs",
+		"container_definitions": "[{\"name\":\"datadog-agent\",\"essential\":true,\"mountPoints\":[{\"sourceVolume\":\"dd-sockets\",\"containerPath\":\"/var/run/datadog\",\"readOnly\":false}]}]",
+		"cpu": "256",
+		"memory": "512",
+		"network_mode": "awsvpc",
+		"pid_mode": "task",
+		"ipc_mode": "",
+		"requires_compatibilities": ["FARGATE"],
+		"ephemeral_storage": [{"size_in_gib": 40}],
+		"runtime_platform": [{"cpu_architecture": "X86_64", "operating_system_family": "LINUX"}],
+		"proxy_configuration": [{"container_name": "app", "type": "APPMESH", "properties": {"ProxyIngressPort": "15000", "AppPorts": "8080"}}],
+		"inference_accelerator": null,
+		"tags": {"dd_ecs_terraform_module": "1.1.1"},
+		"track_latest": false,
+		"volume": [
+			{"name": "dd-sockets", "host_path": "", "docker_volume_configuration": [], "efs_volume_configuration": [], "fsx_windows_file_server_volume_configuration": []},
+			{"name": "efs-storage", "host_path": "", "docker_volume_configuration": [], "fsx_windows_file_server_volume_configuration": [], "efs_volume_configuration": [{
+				"file_system_id": "fs-0123", "root_directory": "/", "transit_encryption": "ENABLED", "transit_encryption_port": 2999,
+				"authorization_config": [{"access_point_id": "fsap-0123", "iam": "ENABLED"}]
+			}]}
+		]
+	}`
+
+	task, err := DecodeFargateTaskOutput([]byte(output))
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(3), task.Revision)
+	assert.Equal(t, types.NetworkModeAwsvpc, task.NetworkMode)
+	assert.Equal(t, types.IpcMode(""), task.IpcMode)
+	assert.Equal(t, []types.Compatibility{types.CompatibilityFargate}, task.RequiresCompatibilities)
+	assert.Equal(t, &types.EphemeralStorage{SizeInGiB: 40}, task.EphemeralStorage)
+	assert.Equal(t, &types.RuntimePlatform{CpuArchitecture: types.CPUArchitectureX8664, OperatingSystemFamily: types.OSFamilyLinux}, task.RuntimePlatform)
+	assert.Equal(t, "1.1.1", task.Tags["dd_ecs_terraform_module"])
+
+	require.NotNil(t, task.ProxyConfiguration)
+	assert.Equal(t, []types.KeyValuePair{
+		{Name: aws.String("AppPorts"), Value: aws.String("8080")},
+		{Name: aws.String("ProxyIngressPort"), Value: aws.String("15000")},
+	}, task.ProxyConfiguration.Properties)
+
+	require.Len(t, task.ContainerDefinitions, 1)
+	ecsassert.AssertMountPoint(t, task.ContainerDefinitions[0], ecsassert.MountDdSocket)
+
+	require.Len(t, task.Volumes, 2)
+	socketVolume, found := ecsassert.GetVolume(task.Volumes, "dd-sockets")
+	assert.True(t, found)
+	assert.Equal(t, types.Volume{Name: aws.String("dd-sockets")}, socketVolume)
+
+	efsVolume, found := ecsassert.GetVolume(task.Volumes, "efs-storage")
+	assert.True(t, found)
+	assert.Equal(t, &types.EFSVolumeConfiguration{
+		FileSystemId:          aws.String("fs-0123"),
+		RootDirectory:         aws.String("/"),
+		TransitEncryption:     types.EFSTransitEncryptionEnabled,
+		TransitEncryptionPort: aws.Int32(2999),
+		AuthorizationConfig:   &types.EFSAuthorizationConfig{AccessPointId: aws.String("fsap-0123"), Iam: types.EFSAuthorizationConfigIAMEnabled},
+	}, efsVolume.EfsVolumeConfiguration)
+}
+
+// TestDecodeEC2TaskOutput tests the decoding of the ecs_ec2 module helper outputs
+func TestDecodeEC2TaskOutput(t *testing.T) {
+	output := `{
+		"family": "terraform-test-agent-only",
+		"network_mode": "bridge",
+		"container_definitions": "[]",
+		"volume": [{"name": "docker_sock", "host_path": "/var/run/docker.sock"}],
+		"service_id": null,
+		"service_desired_count": null,
+		"dogstatsd_env_vars": [{"name": "DD_DOGSTATSD_URL", "value": "unix:///var/run/datadog/dsd.socket"}],
+		"app_dd_sockets_mount": [{"sourceVolume": "dd-sockets", "containerPath": "/var/run/datadog", "readOnly": false}],
+		"app_dd_sockets_volume": [{"name": "dd-sockets", "host_path": "/var/run/datadog"}]
+	}`
+
+	task, err := DecodeEC2TaskOutput([]byte(output))
+	require.NoError(t, err)
+
+	assert.Equal(t, types.NetworkModeBridge, task.NetworkMode)
+	assert.Empty(t, task.ServiceID)
+	assert.Nil(t, task.ServiceDesiredCount)
+	assert.Equal(t, []types.Volume{{Name: aws.String("docker_sock"), Host: &types.HostVolumeProperties{SourcePath: aws.String("/var/run/docker.sock")}}}, task.Volumes)
+	assert.Equal(t, []types.KeyValuePair{{Name: aws.String("DD_DOGSTATSD_URL"), Value: aws.String("unix:///var/run/datadog/dsd.socket")}}, task.DogstatsdEnvVars)
+	assert.Equal(t, []types.MountPoint{ecsassert.MountDdSocket}, task.AppDdSocketsMount)
+	assert.Equal(t, []types.Volume{{Name: aws.String("dd-sockets"), Host: &types.HostVolumeProperties{SourcePath: aws.String("/var/run/datadog")}}}, task.AppDdSocketsVolume)
+}
+
+// TestDecodeContainerDefinitions tests that keys unknown to the ECS API are rejected with the container they belong to
+func TestDecodeContainerDefinitions(t *testing.T) {
+	containers, err := DecodeContainerDefinitions([]byte(`[
+		{"name": "datadog-agent", "memory": 256, "memoryReservation": 128, "essential": true},
+		{"name": "datadog-log-router", "firelensConfiguration": {"type": "fluentbit", "options": {"enable-ecs-log-metadata": "true"}}}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, containers, 2)
+	assert.Equal(t, aws.Int32(256), containers[0].Memory)
+	assert.Equal(t, aws.Int32(128), containers[0].MemoryReservation)
+	assert.Equal(t, types.FirelensConfigurationTypeFluentbit, containers[1].FirelensConfiguration.Type)
+
+	_, err = DecodeContainerDefinitions([]byte(`[
+		{"name": "datadog-agent"},
+		{"name": "cws-instrumentation-init", "memory_limit_mib": 64}
+	]`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `container definition 1 (cws-instrumentation-init)`)
+	assert.Contains(t, err.Error(), `unknown field "memory_limit_mib"`)
+
+	_, err = DecodeContainerDefinitions([]byte(`[{"name": "app", "logConfiguration": {"logDriver": "awslogs", "option": {}}}]`))
+	assert.ErrorContains(t, err, `unknown field "option"`)
+}
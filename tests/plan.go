@@ -0,0 +1,106 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+)
+
+// PlanOnlyEnvVar selects plan-only mode when set to a true value (eg. `TERRAFORM_PLAN_ONLY=true`).
+// In plan-only mode the suites never call AWS: they plan the smoke tests and read the
+// rendered task definitions from the plan instead of from the applied state.
+const PlanOnlyEnvVar = "TERRAFORM_PLAN_ONLY"
+
+// IsPlanOnly reports whether the suites should run in plan-only mode
+func IsPlanOnly() bool {
+	planOnly, err := strconv.ParseBool(os.Getenv(PlanOnlyEnvVar))
+	return err == nil && planOnly
+}
+
+// InitAndPlanOnly runs terraform init, plan -out and show -json, and returns the parsed plan
+func InitAndPlanOnly(t *testing.T, options *terraform.Options) *terraform.PlanStruct {
+	options.PlanFilePath = filepath.Join(t.TempDir(), "plan.out")
+	return terraform.InitAndPlanAndShowWithStruct(t, options)
+}
+
+// PlannedOutputJson mimics terraform.OutputJson for a root output whose value is not known until apply.
+// It returns the attributes of the aws_ecs_task_definition planned by the module the output refers to,
+// which the module outputs mirror. Attributes only known after apply (eg. `arn` or `revision`) are absent.
+func PlannedOutputJson(t *testing.T, plan *terraform.PlanStruct, key string) string {
+	output, err := plannedOutputJsonE(plan, key)
+	require.NoError(t, err)
+	return output
+}
+
+// plannedOutputJsonE is PlannedOutputJson returning errors instead of failing the test
+func plannedOutputJsonE(plan *terraform.PlanStruct, key string) (string, error) {
+	taskDefinition, err := plannedTaskDefinition(plan, key)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(taskDefinition.AttributeValues)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// plannedTaskDefinition returns the aws_ecs_task_definition planned by the module a root output refers to
+func plannedTaskDefinition(plan *terraform.PlanStruct, key string) (*tfjson.StateResource, error) {
+	reference, err := outputModuleReference(plan, key)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(reference, ".", 3)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("output %s does not refer to a module", key)
+	}
+	modulePrefix := parts[0] + "." + parts[1] + "."
+
+	for address, resource := range plan.ResourcePlannedValuesMap {
+		if resource.Mode != tfjson.ManagedResourceMode || resource.Type != "aws_ecs_task_definition" {
+			continue
+		}
+		// Only consider resources declared directly in the referenced module
+		if strings.HasPrefix(address, modulePrefix) && !strings.HasPrefix(strings.TrimPrefix(address, modulePrefix), "module.") {
+			return resource, nil
+		}
+	}
+	return nil, fmt.Errorf("no aws_ecs_task_definition planned in %s for output %s", strings.TrimSuffix(modulePrefix, "."), key)
+}
+
+// outputModuleReference returns the most specific module reference (eg. `module.foo` or `module.foo.network_mode`) of a root output
+func outputModuleReference(plan *terraform.PlanStruct, key string) (string, error) {
+	if plan.RawPlan.Config == nil || plan.RawPlan.Config.RootModule == nil {
+		return "", fmt.Errorf("plan does not contain the root module configuration")
+	}
+	output, found := plan.RawPlan.Config.RootModule.Outputs[key]
+	if !found {
+		return "", fmt.Errorf("output %s not found in plan configuration", key)
+	}
+	if output.Expression == nil || output.Expression.ExpressionData == nil {
+		return "", fmt.Errorf("output %s has no expression", key)
+	}
+
+	// References are listed from the most to the least specific (eg. `module.foo.bar`, then `module.foo`)
+	for _, reference := range output.Expression.References {
+		if strings.HasPrefix(reference, "module.") {
+			return reference, nil
+		}
+	}
+	return "", fmt.Errorf("output %s does not refer to a module", key)
+}
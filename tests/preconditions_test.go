@@ -0,0 +1,276 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// planOnlyProvider configures the AWS provider of a module planned as a root module, without any AWS API call
+const planOnlyProvider = `provider "aws" {
+  region                      = "us-east-1"
+  access_key                  = "terraform-test"
+  secret_key                  = "terraform-test"
+  skip_credentials_validation = true
+  skip_metadata_api_check     = true
+  skip_requesting_account_id  = true
+}
+`
+
+// outputBlock matches the name of an output block of a module
+var outputBlock = regexp.MustCompile(`(?m)^output "([^"]+)"`)
+
+// moduleBaseVars are the minimal valid inputs of each module, overridden by every rejected input case
+var moduleBaseVars = map[string]map[string]interface{}{
+	"ecs_fargate": {
+		"dd_api_key":            "test-api-key",
+		"family":                "terraform-test-rejected-inputs",
+		"container_definitions": `[{"name":"app","image":"nginx","essential":true,"entryPoint":["nginx"]}]`,
+	},
+	"ecs_ec2": {
+		"dd_api_key":     "test-api-key",
+		"family":         "terraform-test-rejected-inputs",
+		"create_service": false,
+	},
+}
+
+// windowsRuntimePlatform is a runtime_platform input on Windows
+var windowsRuntimePlatform = map[string]interface{}{
+	"operating_system_family": "WINDOWS_SERVER_2022_CORE",
+	"cpu_architecture":        "X86_64",
+}
+
+// rejectedInputCases are invalid inputs, with the error_message of the precondition or validation rejecting them
+var rejectedInputCases = []struct {
+	name         string
+	module       string
+	vars         map[string]interface{}
+	errorMessage string
+}{
+	{
+		name:         "fargate both API key options",
+		module:       "ecs_fargate",
+		vars:         map[string]interface{}{"dd_api_key_secret": map[string]interface{}{"arn": "arn:aws:secretsmanager:us-east-1:123456789012:secret:dd-api-key"}},
+		errorMessage: "You must provide only one of the two Datadog API key options: `dd_api_key` or `dd_api_key_secret`.",
+	},
+	{
+		name:         "fargate no API key option",
+		module:       "ecs_fargate",
+		vars:         map[string]interface{}{"dd_api_key": nil},
+		errorMessage: "You must provide only one of the two Datadog API key options: `dd_api_key` or `dd_api_key_secret`.",
+	},
+	{
+		name:         "fargate CWS without agent dependency",
+		module:       "ecs_fargate",
+		vars:         map[string]interface{}{"dd_cws": map[string]interface{}{"enabled": true}, "dd_is_datadog_dependency_enabled": false},
+		errorMessage: "The Datadog Agent container dependency must be enabled for CWS to be stable. Please set `dd_is_datadog_dependency_enabled` to `true`.",
+	},
+	{
+		name:         "fargate log collection on Windows",
+		module:       "ecs_fargate",
+		vars:         map[string]interface{}{"dd_log_collection": map[string]interface{}{"enabled": true}, "runtime_platform": windowsRuntimePlatform},
+		errorMessage: "Log collection is not supported on Windows. Please set `dd_log_collection.enabled` to `false`.",
+	},
+	{
+		name:         "fargate read-only root filesystem on Windows",
+		module:       "ecs_fargate",
+		vars:         map[string]interface{}{"dd_readonly_root_filesystem": true, "runtime_platform": windowsRuntimePlatform},
+		errorMessage: "Readonly root filesystem is only supported on Linux. Please set `dd_readonly_root_filesystem` to `false`.",
+	},
+	{
+		name:         "fargate bad dogstatsd cardinality",
+		module:       "ecs_fargate",
+		vars:         map[string]interface{}{"dd_dogstatsd": map[string]interface{}{"dogstatsd_cardinality": "medium"}},
+		errorMessage: "The Datadog Dogstatsd cardinality must be one of 'low', 'orchestrator', 'high', or null.",
+	},
+	{
+		name:         "fargate bad checks cardinality",
+		module:       "ecs_fargate",
+		vars:         map[string]interface{}{"dd_checks_cardinality": "medium"},
+		errorMessage: "The Datadog Agent checks cardinality must be one of 'low', 'orchestrator', 'high', or null.",
+	},
+	{
+		name:         "ec2 both API key options",
+		module:       "ecs_ec2",
+		vars:         map[string]interface{}{"dd_api_key_secret": map[string]interface{}{"arn": "arn:aws:secretsmanager:us-east-1:123456789012:secret:dd-api-key"}},
+		errorMessage: "You must provide exactly one of the two Datadog API key options: 'dd_api_key' or 'dd_api_key_secret'.",
+	},
+	{
+		name:         "ec2 log collection on Windows",
+		module:       "ecs_ec2",
+		vars:         map[string]interface{}{"dd_log_collection": map[string]interface{}{"enabled": true}, "runtime_platform": windowsRuntimePlatform},
+		errorMessage: "Log collection is not supported on Windows. Please set dd_log_collection.enabled to false.",
+	},
+	{
+		name:         "ec2 bad dogstatsd cardinality",
+		module:       "ecs_ec2",
+		vars:         map[string]interface{}{"dd_dogstatsd": map[string]interface{}{"dogstatsd_cardinality": "medium"}},
+		errorMessage: "The Datadog Dogstatsd cardinality must be one of 'low', 'orchestrator', 'high', or null.",
+	},
+	{
+		name:         "ec2 bad checks cardinality",
+		module:       "ecs_ec2",
+		vars:         map[string]interface{}{"dd_checks_cardinality": "medium"},
+		errorMessage: "The Datadog Agent checks cardinality must be one of 'low', 'orchestrator', 'high', or null.",
+	},
+	{
+		name:         "ec2 bad log level",
+		module:       "ecs_ec2",
+		vars:         map[string]interface{}{"dd_log_level": "verbose"},
+		errorMessage: "dd_log_level must be one of: trace, debug, info, warn, error, critical, off",
+	},
+	{
+		name:         "ec2 service without cluster",
+		module:       "ecs_ec2",
+		vars:         map[string]interface{}{"create_service": true},
+		errorMessage: "cluster_arn must be provided when create_service is true.",
+	},
+	{
+		name:         "ec2 dogstatsd without transport",
+		module:       "ecs_ec2",
+		vars:         map[string]interface{}{"dd_dogstatsd": map[string]interface{}{"socket_enabled": false, "tcp_enabled": false}},
+		errorMessage: "DogStatsD is enabled but neither UDS (socket_enabled) nor TCP (tcp_enabled) transport is configured. Set at least one to true.",
+	},
+	{
+		name:         "ec2 APM without transport",
+		module:       "ecs_ec2",
+		vars:         map[string]interface{}{"dd_apm": map[string]interface{}{"socket_enabled": false, "tcp_enabled": false}},
+		errorMessage: "APM is enabled but neither UDS (socket_enabled) nor TCP (tcp_enabled) transport is configured. Set at least one to true.",
+	},
+}
+
+// TestBaseInputsAccepted plans each module with its base inputs alone, so a rejected input case is only
+// rejected by the rule it targets
+func TestBaseInputsAccepted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping the base inputs plans in short mode")
+	}
+
+	for module, vars := range moduleBaseVars {
+		t.Run(module, func(t *testing.T) {
+			diagnostics, err := planModuleDiagnostics(t, initModuleAsRoot(t, module), vars)
+			for _, diagnostic := range diagnostics {
+				if diagnostic.Severity == tfjson.DiagnosticSeverityError {
+					t.Errorf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+				}
+			}
+			require.NoError(t, err, "Plan of the base inputs should succeed")
+		})
+	}
+}
+
+// TestRejectedInputs plans the modules with invalid inputs and checks the exact error message of each rejection
+func TestRejectedInputs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping the rejected inputs plans in short mode")
+	}
+
+	moduleDirs := map[string]string{}
+	for module := range moduleBaseVars {
+		moduleDirs[module] = initModuleAsRoot(t, module)
+	}
+
+	for _, tc := range rejectedInputCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vars := map[string]interface{}{}
+			for name, value := range moduleBaseVars[tc.module] {
+				vars[name] = value
+			}
+			for name, value := range tc.vars {
+				vars[name] = value
+			}
+
+			diagnostics, err := planModuleDiagnostics(t, moduleDirs[tc.module], vars)
+			require.Error(t, err, "Plan should be rejected")
+
+			var details []string
+			for _, diagnostic := range diagnostics {
+				if diagnostic.Severity != tfjson.DiagnosticSeverityError {
+					continue
+				}
+				// Variable validations append where the rule was checked after the error message
+				details = append(details, strings.SplitN(diagnostic.Detail, "\n\n", 2)[0])
+			}
+			assert.Contains(t, details, tc.errorMessage, "Plan should fail with the error message of the rejecting rule")
+		})
+	}
+}
+
+// initModuleAsRoot copies a module to a temporary directory with a plan-only provider and sensitive outputs,
+// and runs terraform init
+func initModuleAsRoot(t *testing.T, module string) string {
+	dir, err := files.CopyTerraformFolderToDest(filepath.Join("..", "modules", module), t.TempDir(), module)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "provider.tf"), []byte(planOnlyProvider), 0o644))
+
+	// A root module output must be marked sensitive when it refers to a sensitive input (eg. dd_api_key)
+	moduleOutputs, err := os.ReadFile(filepath.Join(dir, "outputs.tf"))
+	require.NoError(t, err)
+	var overrides strings.Builder
+	for _, match := range outputBlock.FindAllSubmatch(moduleOutputs, -1) {
+		fmt.Fprintf(&overrides, "output %q {\n  sensitive = true\n}\n", match[1])
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "outputs_override.tf"), []byte(overrides.String()), 0o644))
+
+	terraform.Init(t, &terraform.Options{TerraformDir: dir, NoColor: true, Logger: logger.Discard})
+	return dir
+}
+
+// planModuleDiagnostics plans an initialized module and returns the diagnostics of its JSON output
+func planModuleDiagnostics(t *testing.T, dir string, vars map[string]interface{}) ([]tfjson.Diagnostic, error) {
+	options := &terraform.Options{TerraformDir: dir, NoColor: true, VarFiles: []string{writeVarFile(t, vars)}, Logger: logger.Discard}
+	output, planErr := terraform.RunTerraformCommandE(t, options, terraform.FormatArgs(options, "plan", "-input=false", "-lock=false", "-json")...)
+
+	var diagnostics []tfjson.Diagnostic
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var message struct {
+			Type       string             `json:"type"`
+			Diagnostic *tfjson.Diagnostic `json:"diagnostic"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &message) == nil && message.Type == "diagnostic" && message.Diagnostic != nil {
+			diagnostics = append(diagnostics, *message.Diagnostic)
+		}
+	}
+	return diagnostics, planErr
+}
+
+// planModuleAsRoot plans an initialized module and returns the parsed plan
+func planModuleAsRoot(t *testing.T, dir string, vars map[string]interface{}) *terraform.PlanStruct {
+	options := &terraform.Options{
+		TerraformDir: dir,
+		NoColor:      true,
+		VarFiles:     []string{writeVarFile(t, vars)},
+		PlanFilePath: filepath.Join(t.TempDir(), "plan.out"),
+		Logger:       logger.Discard,
+	}
+	terraform.Plan(t, options)
+	return terraform.ShowWithStruct(t, options)
+}
+
+// writeVarFile writes the inputs as JSON so null values and nested objects are passed unchanged
+func writeVarFile(t *testing.T, vars map[string]interface{}) string {
+	data, err := json.Marshal(vars)
+	require.NoError(t, err)
+	varFile := filepath.Join(t.TempDir(), "inputs.tfvars.json")
+	require.NoError(t, os.WriteFile(varFile, data, 0o644))
+	return varFile
+}
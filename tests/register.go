@@ -0,0 +1,303 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Rules checked by ValidateRegisterInput
+const (
+	RuleSDKParameter       = "sdk-parameter"
+	RuleFargateCPUMemory   = "fargate-cpu-memory"
+	RuleContainerMemorySum = "container-memory-sum"
+	RuleUniquePortMapping  = "unique-port-mapping"
+	RuleWindowsUnsupported = "windows-unsupported"
+)
+
+// fargateWindowsMinimumCPU is the smallest task cpu Fargate runs Windows containers with
+const fargateWindowsMinimumCPU = 1024
+
+// fargateMemoryRange is the memory (in MiB) Fargate allows for a task cpu value: from Min to Max by Step,
+// or only the Allowed values
+type fargateMemoryRange struct {
+	Min, Max, Step int
+	Allowed        []int
+}
+
+// fargateMemoryRanges are the supported Fargate task sizes, by cpu units
+var fargateMemoryRanges = map[int]fargateMemoryRange{
+	256:   {Allowed: []int{512, 1024, 2048}},
+	512:   {Min: 1024, Max: 4096, Step: 1024},
+	1024:  {Min: 2048, Max: 8192, Step: 1024},
+	2048:  {Min: 4096, Max: 16384, Step: 1024},
+	4096:  {Min: 8192, Max: 30720, Step: 1024},
+	8192:  {Min: 16384, Max: 61440, Step: 4096},
+	16384: {Min: 32768, Max: 122880, Step: 8192},
+}
+
+// Contains reports whether the range allows the memory
+func (r fargateMemoryRange) Contains(memory int) bool {
+	if r.Allowed != nil {
+		return slices.Contains(r.Allowed, memory)
+	}
+	return memory >= r.Min && memory <= r.Max && (memory-r.Min)%r.Step == 0
+}
+
+// String describes the memory values of the range
+func (r fargateMemoryRange) String() string {
+	if r.Allowed != nil {
+		values := make([]string, len(r.Allowed))
+		for i, memory := range r.Allowed {
+			values[i] = strconv.Itoa(memory)
+		}
+		return strings.Join(values, ", ") + " MiB"
+	}
+	return fmt.Sprintf("%d to %d MiB in steps of %d", r.Min, r.Max, r.Step)
+}
+
+// errSkipRegister stops a RegisterTaskDefinition call once its input is validated, before anything is sent
+var errSkipRegister = errors.New("register task definition skipped after validation")
+
+// RegisterInput returns the input registering the task definition with the ECS API
+func (task TaskDefinitionOutput) RegisterInput() *ecs.RegisterTaskDefinitionInput {
+	input := &ecs.RegisterTaskDefinitionInput{
+		Family:                  optionalString(task.Family),
+		ContainerDefinitions:    task.ContainerDefinitions,
+		NetworkMode:             task.NetworkMode,
+		PidMode:                 task.PidMode,
+		IpcMode:                 task.IpcMode,
+		ExecutionRoleArn:        optionalString(task.ExecutionRoleArn),
+		TaskRoleArn:             optionalString(task.TaskRoleArn),
+		RequiresCompatibilities: task.RequiresCompatibilities,
+		PlacementConstraints:    task.PlacementConstraints,
+		ProxyConfiguration:      task.ProxyConfiguration,
+		Volumes:                 task.Volumes,
+	}
+	for _, key := range sortedKeys(task.Tags) {
+		input.Tags = append(input.Tags, types.Tag{Key: aws.String(key), Value: aws.String(task.Tags[key])})
+	}
+	return input
+}
+
+// RegisterInput returns the input registering the task definition with the ECS API, including the Fargate task size
+func (task FargateTaskOutput) RegisterInput() *ecs.RegisterTaskDefinitionInput {
+	input := task.TaskDefinitionOutput.RegisterInput()
+	input.Cpu = optionalString(task.Cpu)
+	input.Memory = optionalString(task.Memory)
+	input.EphemeralStorage = task.EphemeralStorage
+	input.RuntimePlatform = task.RuntimePlatform
+	if task.EnableFaultInjection {
+		input.EnableFaultInjection = aws.Bool(true)
+	}
+	return input
+}
+
+// ValidateRegisterInput returns the violations of the parameter validation the ECS SDK runs before sending
+// a RegisterTaskDefinition request, and of the service rules the SDK does not encode. Nothing is sent to AWS.
+func ValidateRegisterInput(input *ecs.RegisterTaskDefinitionInput) []Violation {
+	violations := sdkParameterViolations(input)
+	violations = append(violations, fargateTaskSizeViolations(input)...)
+	violations = append(violations, containerMemoryViolations(input)...)
+	violations = append(violations, portMappingViolations(input)...)
+	violations = append(violations, windowsViolations(input)...)
+	return violations
+}
+
+// AssertValidRegisterInput fails the test for every violation ECS would reject when registering the input
+func AssertValidRegisterInput(t *testing.T, input *ecs.RegisterTaskDefinitionInput) {
+	for _, violation := range ValidateRegisterInput(input) {
+		t.Errorf("Task definition %s would be rejected by ECS: %s", aws.ToString(input.Family), violation)
+	}
+}
+
+// sdkParameterViolations runs RegisterTaskDefinition on a client stopping the request right after
+// its input validation, and reports every invalid parameter
+func sdkParameterViolations(input *ecs.RegisterTaskDefinitionInput) []Violation {
+	client := ecs.New(ecs.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		APIOptions: []func(*middleware.Stack) error{
+			func(stack *middleware.Stack) error {
+				return stack.Serialize.Add(middleware.SerializeMiddlewareFunc("SkipRegister",
+					func(context.Context, middleware.SerializeInput, middleware.SerializeHandler) (middleware.SerializeOutput, middleware.Metadata, error) {
+						return middleware.SerializeOutput{}, middleware.Metadata{}, errSkipRegister
+					}), middleware.Before)
+			},
+		},
+	})
+
+	_, err := client.RegisterTaskDefinition(context.Background(), input)
+	if errors.Is(err, errSkipRegister) {
+		return nil
+	}
+	var invalidParams smithy.InvalidParamsError
+	if !errors.As(err, &invalidParams) {
+		return []Violation{{RuleSDKParameter, "", fmt.Sprintf("unexpected validation error: %v", err)}}
+	}
+
+	var violations []Violation
+	for _, paramErr := range invalidParams.Errs() {
+		violations = append(violations, Violation{RuleSDKParameter, "", paramErr.Error()})
+	}
+	return violations
+}
+
+// fargateTaskSizeViolations checks a Fargate task sets one of the cpu and memory combinations Fargate supports
+func fargateTaskSizeViolations(input *ecs.RegisterTaskDefinitionInput) []Violation {
+	if !slices.Contains(input.RequiresCompatibilities, types.CompatibilityFargate) {
+		return nil
+	}
+	cpu, cpuErr := parseTaskSize(aws.ToString(input.Cpu), "vcpu")
+	memory, memoryErr := parseTaskSize(aws.ToString(input.Memory), "gb")
+	if cpuErr != nil || memoryErr != nil {
+		return []Violation{{RuleFargateCPUMemory, "", fmt.Sprintf("requires a task cpu and memory, got cpu %q and memory %q", aws.ToString(input.Cpu), aws.ToString(input.Memory))}}
+	}
+
+	var violations []Violation
+	memoryRange, found := fargateMemoryRanges[cpu]
+	if !found {
+		violations = append(violations, Violation{RuleFargateCPUMemory, "", fmt.Sprintf("cpu %d is not a Fargate task size", cpu)})
+	} else if !memoryRange.Contains(memory) {
+		violations = append(violations, Violation{RuleFargateCPUMemory, "", fmt.Sprintf("memory %d is not supported with cpu %d, which allows %s", memory, cpu, memoryRange)})
+	}
+	if isWindows(input) && cpu < fargateWindowsMinimumCPU {
+		violations = append(violations, Violation{RuleFargateCPUMemory, "", fmt.Sprintf("cpu %d is below the %d required by Windows tasks", cpu, fargateWindowsMinimumCPU)})
+	}
+	return violations
+}
+
+// parseTaskSize parses a task cpu or memory value, given in units (eg. "1024") or with a unit (eg. "1 vCPU", "2 GB")
+func parseTaskSize(value string, unit string) (int, error) {
+	value = strings.TrimSpace(value)
+	if number, found := strings.CutSuffix(strings.ToLower(value), unit); found {
+		size, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		return int(size * 1024), err
+	}
+	return strconv.Atoi(value)
+}
+
+// containerMemoryViolations checks the memory reserved by the containers fits in the task memory.
+// A container reserves its memory limit, or its soft limit when it has none.
+func containerMemoryViolations(input *ecs.RegisterTaskDefinitionInput) []Violation {
+	taskMemory, err := parseTaskSize(aws.ToString(input.Memory), "gb")
+	if err != nil {
+		return nil
+	}
+
+	total := 0
+	var reserved []string
+	for _, container := range input.ContainerDefinitions {
+		memory := container.Memory
+		if memory == nil {
+			memory = container.MemoryReservation
+		}
+		if memory != nil {
+			total += int(*memory)
+			reserved = append(reserved, fmt.Sprintf("%s=%d", aws.ToString(container.Name), *memory))
+		}
+	}
+	if total > taskMemory {
+		return []Violation{{RuleContainerMemorySum, "", fmt.Sprintf("containers reserve %d MiB (%s), more than the %d MiB of the task", total, strings.Join(reserved, ", "), taskMemory)}}
+	}
+	return nil
+}
+
+// portMappingViolations checks no two port mappings of the task use the same port and protocol: the container
+// port in awsvpc and host network modes, where containers share the task network namespace, the host port in
+// bridge mode, where mappings without a host port get a dynamic one
+func portMappingViolations(input *ecs.RegisterTaskDefinitionInput) []Violation {
+	bridge := input.NetworkMode != types.NetworkModeAwsvpc && input.NetworkMode != types.NetworkModeHost
+	var violations []Violation
+	owners := map[string]string{}
+	for _, container := range input.ContainerDefinitions {
+		name := aws.ToString(container.Name)
+		for _, mapping := range container.PortMappings {
+			port := mapping.ContainerPort
+			if bridge {
+				port = mapping.HostPort
+			}
+			if aws.ToInt32(port) == 0 {
+				continue
+			}
+			protocol := mapping.Protocol
+			if protocol == "" {
+				protocol = types.TransportProtocolTcp
+			}
+			key := fmt.Sprintf("%d/%s", *port, protocol)
+			if owner, found := owners[key]; found {
+				kind := "port"
+				if bridge {
+					kind = "host port"
+				}
+				violations = append(violations, Violation{RuleUniquePortMapping, name, fmt.Sprintf("maps %s %s already mapped by container %s", kind, key, owner)})
+				continue
+			}
+			owners[key] = name
+		}
+	}
+	return violations
+}
+
+// windowsViolations checks a Windows task does not set parameters Windows containers do not support
+func windowsViolations(input *ecs.RegisterTaskDefinitionInput) []Violation {
+	if !isWindows(input) {
+		return nil
+	}
+
+	var violations []Violation
+	// ECS accepts the task namespace Fargate tasks always use, applied Windows smoke tests included
+	if input.PidMode == types.PidModeHost {
+		violations = append(violations, Violation{RuleWindowsUnsupported, "", "sets pidMode host"})
+	}
+	if input.IpcMode != "" {
+		violations = append(violations, Violation{RuleWindowsUnsupported, "", "sets ipcMode"})
+	}
+	if input.ProxyConfiguration != nil {
+		violations = append(violations, Violation{RuleWindowsUnsupported, "", "sets proxyConfiguration"})
+	}
+	for _, container := range input.ContainerDefinitions {
+		name := aws.ToString(container.Name)
+		unsupported := map[string]bool{
+			"linuxParameters":        container.LinuxParameters != nil,
+			"privileged":             aws.ToBool(container.Privileged),
+			"readonlyRootFilesystem": aws.ToBool(container.ReadonlyRootFilesystem),
+			"user":                   container.User != nil,
+			"firelensConfiguration":  container.FirelensConfiguration != nil,
+		}
+		for _, field := range sortedKeys(unsupported) {
+			if unsupported[field] {
+				violations = append(violations, Violation{RuleWindowsUnsupported, name, "sets " + field})
+			}
+		}
+	}
+	return violations
+}
+
+func isWindows(input *ecs.RegisterTaskDefinitionInput) bool {
+	return input.RuntimePlatform != nil && strings.HasPrefix(string(input.RuntimePlatform.OperatingSystemFamily), "WINDOWS")
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
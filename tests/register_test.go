@@ -0,0 +1,178 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registerTestTask returns a valid Fargate task for the register input rules to break
+func registerTestTask() FargateTaskOutput {
+	return FargateTaskOutput{
+		TaskDefinitionOutput: TaskDefinitionOutput{
+			Family:                  "terraform-test-register",
+			NetworkMode:             types.NetworkModeAwsvpc,
+			RequiresCompatibilities: []types.Compatibility{types.CompatibilityFargate},
+			Tags:                    map[string]string{"dd_ecs_terraform_module": "1.1.1"},
+			ContainerDefinitions: []types.ContainerDefinition{
+				{
+					Name:   aws.String("datadog-agent"),
+					Image:  aws.String("public.ecr.aws/datadog/agent:latest"),
+					Memory: aws.Int32(256),
+					PortMappings: []types.PortMapping{
+						{ContainerPort: aws.Int32(8125), Protocol: types.TransportProtocolUdp},
+						{ContainerPort: aws.Int32(8126), Protocol: types.TransportProtocolTcp},
+					},
+				},
+				{
+					Name:              aws.String("app"),
+					Image:             aws.String("nginx"),
+					MemoryReservation: aws.Int32(128),
+					PortMappings:      []types.PortMapping{{ContainerPort: aws.Int32(8125)}},
+				},
+			},
+		},
+		Cpu:    "256",
+		Memory: "512",
+	}
+}
+
+// rules returns the rule of each violation
+func rules(violations []Violation) []string {
+	var names []string
+	for _, violation := range violations {
+		names = append(names, violation.Rule)
+	}
+	return names
+}
+
+// TestFargateRegisterInput tests that the Fargate task size is registered with the shared attributes
+func TestFargateRegisterInput(t *testing.T) {
+	task := registerTestTask()
+	task.RuntimePlatform = &types.RuntimePlatform{OperatingSystemFamily: types.OSFamilyLinux}
+
+	input := task.RegisterInput()
+	assert.Equal(t, "terraform-test-register", aws.ToString(input.Family))
+	assert.Equal(t, "256", aws.ToString(input.Cpu))
+	assert.Equal(t, "512", aws.ToString(input.Memory))
+	assert.Nil(t, input.ExecutionRoleArn)
+	assert.Nil(t, input.EnableFaultInjection)
+	assert.Equal(t, types.OSFamilyLinux, input.RuntimePlatform.OperatingSystemFamily)
+	assert.Equal(t, []types.Tag{{Key: aws.String("dd_ecs_terraform_module"), Value: aws.String("1.1.1")}}, input.Tags)
+	assert.Empty(t, ValidateRegisterInput(input))
+}
+
+// TestValidateRegisterInput tests the SDK parameter validation and each service rule on a broken task
+func TestValidateRegisterInput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		breaks   func(task *FargateTaskOutput)
+		expected []string
+	}{
+		{
+			name: "missing family and log driver",
+			breaks: func(task *FargateTaskOutput) {
+				task.Family = ""
+				task.ContainerDefinitions[1].LogConfiguration = &types.LogConfiguration{Options: map[string]string{"Name": "datadog"}}
+			},
+			expected: []string{RuleSDKParameter, RuleSDKParameter},
+		},
+		{
+			name:     "memory not allowed with cpu",
+			breaks:   func(task *FargateTaskOutput) { task.Cpu, task.Memory = "256", "4096" },
+			expected: []string{RuleFargateCPUMemory},
+		},
+		{
+			name:     "memory between the sizes allowed with the smallest cpu",
+			breaks:   func(task *FargateTaskOutput) { task.Memory = "1536" },
+			expected: []string{RuleFargateCPUMemory},
+		},
+		{
+			name:     "unsupported cpu",
+			breaks:   func(task *FargateTaskOutput) { task.Cpu = "300" },
+			expected: []string{RuleFargateCPUMemory},
+		},
+		{
+			name:     "missing task size",
+			breaks:   func(task *FargateTaskOutput) { task.Cpu = "" },
+			expected: []string{RuleFargateCPUMemory},
+		},
+		{
+			name:     "task size with units",
+			breaks:   func(task *FargateTaskOutput) { task.Cpu, task.Memory = "1 vCPU", "2 GB" },
+			expected: nil,
+		},
+		{
+			name:     "containers reserve more than the task memory",
+			breaks:   func(task *FargateTaskOutput) { task.ContainerDefinitions[1].MemoryReservation = aws.Int32(300) },
+			expected: []string{RuleContainerMemorySum},
+		},
+		{
+			name: "port mapped twice",
+			breaks: func(task *FargateTaskOutput) {
+				task.ContainerDefinitions[1].PortMappings = []types.PortMapping{{ContainerPort: aws.Int32(8126)}}
+			},
+			expected: []string{RuleUniquePortMapping},
+		},
+		{
+			name: "container port mapped twice with dynamic host ports in bridge mode",
+			breaks: func(task *FargateTaskOutput) {
+				task.NetworkMode = types.NetworkModeBridge
+				task.RequiresCompatibilities = []types.Compatibility{types.CompatibilityEc2}
+				task.ContainerDefinitions[1].PortMappings = []types.PortMapping{{ContainerPort: aws.Int32(8126)}}
+			},
+			expected: nil,
+		},
+		{
+			name: "host port mapped twice in bridge mode",
+			breaks: func(task *FargateTaskOutput) {
+				task.NetworkMode = types.NetworkModeBridge
+				task.RequiresCompatibilities = []types.Compatibility{types.CompatibilityEc2}
+				task.ContainerDefinitions[0].PortMappings[1].HostPort = aws.Int32(8126)
+				task.ContainerDefinitions[1].PortMappings = []types.PortMapping{{ContainerPort: aws.Int32(80), HostPort: aws.Int32(8126)}}
+			},
+			expected: []string{RuleUniquePortMapping},
+		},
+		{
+			name: "windows task too small with linux fields",
+			breaks: func(task *FargateTaskOutput) {
+				task.Cpu, task.Memory = "512", "1024"
+				task.PidMode = types.PidModeHost
+				task.RuntimePlatform = &types.RuntimePlatform{OperatingSystemFamily: types.OSFamilyWindowsServer2022Core}
+				task.ContainerDefinitions[0].User = aws.String("0")
+				task.ContainerDefinitions[0].ReadonlyRootFilesystem = aws.Bool(true)
+				task.ContainerDefinitions[1].ReadonlyRootFilesystem = aws.Bool(false)
+			},
+			expected: []string{RuleFargateCPUMemory, RuleWindowsUnsupported, RuleWindowsUnsupported, RuleWindowsUnsupported},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := registerTestTask()
+			tc.breaks(&task)
+			violations := ValidateRegisterInput(task.RegisterInput())
+			assert.Equal(t, tc.expected, rules(violations), "Unexpected violations %v", violations)
+		})
+	}
+}
+
+// TestValidateRegisterInputMessages tests that violations name the parameters and containers at fault
+func TestValidateRegisterInputMessages(t *testing.T) {
+	task := registerTestTask()
+	task.ContainerDefinitions[1].PortMappings = []types.PortMapping{{ContainerPort: aws.Int32(8126)}}
+	task.ContainerDefinitions[1].LogConfiguration = &types.LogConfiguration{}
+
+	violations := ValidateRegisterInput(task.RegisterInput())
+	require.Len(t, violations, 2)
+	assert.Contains(t, violations[0].Message, "ContainerDefinitions[1].LogConfiguration.LogDriver")
+	assert.Equal(t, "[unique-port-mapping] container app: maps port 8126/tcp already mapped by container datadog-agent", violations[1].String())
+}
@@ -0,0 +1,359 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// scenariosDir holds the scenarios declared in YAML, one file per scenario named after it
+const scenariosDir = "scenarios"
+
+// Scenario is a smoke test declared once: the module inputs rendered to the smoke test directory
+// and the task definition the suites expect the module to render from them
+type Scenario struct {
+	// Name names the smoke test file, its output and the task family, after the test prefix
+	Name string `yaml:"-"`
+	// Module is the module under test, and the smoke test directory the scenario is rendered to (eg. ecs_fargate)
+	Module      string `yaml:"module"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	// Inputs are the module arguments. A string holding a single `${expression}` is rendered as the bare
+	// expression (eg. `${var.dd_api_key}`). dd_api_key, dd_site and family are set to the smoke test defaults if omitted.
+	Inputs map[string]interface{} `yaml:"inputs"`
+	// Containers are the complete list of containers of the task definition, with their expected settings
+	Containers []ExpectedContainer `yaml:"containers"`
+
+	// source is the file the scenario is declared in
+	source string
+}
+
+// ExpectedContainer is a container of a scenario. Unset fields are not asserted.
+type ExpectedContainer struct {
+	Name         string            `yaml:"name"`
+	Image        string            `yaml:"image"`
+	User         string            `yaml:"user"`
+	Essential    *bool             `yaml:"essential"`
+	Command      []string          `yaml:"command"`
+	EntryPoint   []string          `yaml:"entry_point"`
+	Env          map[string]string `yaml:"env"`
+	AbsentEnv    []string          `yaml:"absent_env"`
+	Secrets      map[string]string `yaml:"secrets"`
+	DockerLabels map[string]string `yaml:"docker_labels"`
+	// Mounts are `volume:/container/path`, with a `:ro` suffix for read-only mount points
+	Mounts []string `yaml:"mounts"`
+	// Ports are `containerPort/protocol`, or `containerPort:hostPort/protocol` when they differ
+	Ports []string `yaml:"ports"`
+	// DependsOn maps the containers this one depends on to the condition (eg. HEALTHY)
+	DependsOn    map[string]string `yaml:"depends_on"`
+	LogDriver    string            `yaml:"log_driver"`
+	LogOptions   map[string]string `yaml:"log_options"`
+	Firelens     string            `yaml:"firelens"`
+	Capabilities []string          `yaml:"capabilities"`
+}
+
+// jsonEncodedInputs are the module arguments taking a JSON document, rendered with jsonencode
+var jsonEncodedInputs = map[string]bool{"container_definitions": true}
+
+// LoadScenarios returns the scenarios of a module declared in the YAML files of dir, sorted by name
+func LoadScenarios(dir string, module string) ([]Scenario, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var scenarios []Scenario
+	for _, path := range paths {
+		scenario, err := loadScenario(path)
+		if err != nil {
+			return nil, err
+		}
+		if scenario.Module == module {
+			scenarios = append(scenarios, scenario)
+		}
+	}
+	slices.SortFunc(scenarios, func(a, b Scenario) int { return strings.Compare(a.Name, b.Name) })
+	return scenarios, nil
+}
+
+func loadScenario(path string) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+	var scenario Scenario
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// A misspelled expectation would otherwise be silently skipped
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&scenario); err != nil {
+		return Scenario{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	scenario.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	scenario.source = filepath.ToSlash(filepath.Join("tests", path))
+	if err := scenario.validate(); err != nil {
+		return Scenario{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return scenario, nil
+}
+
+// validate checks the scenario can be rendered and its expectations parsed
+func (sc Scenario) validate() error {
+	if sc.Name == "" || sc.Module == "" {
+		return fmt.Errorf("scenario %q must have a name and a module", sc.Name)
+	}
+	if len(sc.Containers) == 0 {
+		return fmt.Errorf("scenario %s expects no container", sc.Name)
+	}
+	for _, container := range sc.Containers {
+		for _, mount := range container.Mounts {
+			if _, err := parseMount(mount); err != nil {
+				return fmt.Errorf("container %s: %w", container.Name, err)
+			}
+		}
+		for _, port := range container.Ports {
+			if _, err := parsePort(port); err != nil {
+				return fmt.Errorf("container %s: %w", container.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// parseMount parses a `volume:/container/path[:ro]` mount point
+func parseMount(mount string) (types.MountPoint, error) {
+	parts := strings.Split(mount, ":")
+	if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != "ro") {
+		return types.MountPoint{}, fmt.Errorf("mount %q should be volume:/container/path, with an optional :ro suffix", mount)
+	}
+	return types.MountPoint{SourceVolume: aws.String(parts[0]), ContainerPath: aws.String(parts[1]), ReadOnly: aws.Bool(len(parts) == 3)}, nil
+}
+
+// parsePort parses a `containerPort[:hostPort]/protocol` port mapping
+func parsePort(port string) (types.PortMapping, error) {
+	ports, protocol, found := strings.Cut(port, "/")
+	containerPort, hostPort, hasHostPort := strings.Cut(ports, ":")
+	if !hasHostPort {
+		hostPort = containerPort
+	}
+	cp, err := strconv.ParseInt(containerPort, 10, 32)
+	if err != nil || !found {
+		return types.PortMapping{}, fmt.Errorf("port %q should be containerPort[:hostPort]/protocol", port)
+	}
+	hp, err := strconv.ParseInt(hostPort, 10, 32)
+	if err != nil {
+		return types.PortMapping{}, fmt.Errorf("port %q should be containerPort[:hostPort]/protocol", port)
+	}
+	return types.PortMapping{ContainerPort: aws.Int32(int32(cp)), HostPort: aws.Int32(int32(hp)), Protocol: types.TransportProtocol(protocol)}, nil
+}
+
+// SmokeTestPath returns the smoke test file the scenario is rendered to, relative to the tests directory
+func (sc Scenario) SmokeTestPath() string {
+	return filepath.Join("..", "smoke_tests", sc.Module, sc.Name+".tf")
+}
+
+// moduleName is the name of the module block of the scenario
+func (sc Scenario) moduleName() string {
+	return "dd_task_" + strings.ReplaceAll(sc.Name, "-", "_")
+}
+
+// RenderHCL renders the smoke test file of the scenario, formatted as `terraform fmt` does
+func (sc Scenario) RenderHCL() []byte {
+	inputs := map[string]interface{}{
+		"dd_api_key": "${var.dd_api_key}",
+		"dd_site":    "${var.dd_site}",
+		"family":     "${var.test_prefix}-" + sc.Name,
+	}
+	for key, value := range sc.Inputs {
+		inputs[key] = value
+	}
+
+	var b strings.Builder
+	b.WriteString(`# Unless explicitly stated otherwise all files in this repository are licensed
+# under the Apache License Version 2.0.
+# This product includes software developed at Datadog (https://www.datadoghq.com/).
+# Copyright 2025-present Datadog, Inc.
+
+`)
+	fmt.Fprintf(&b, "# Code generated from %s by TestRenderScenarios. DO NOT EDIT.\n\n", sc.source)
+	b.WriteString("################################################################################\n")
+	fmt.Fprintf(&b, "# Task Definition: %s\n", sc.Title)
+	b.WriteString("################################################################################\n\n")
+	if sc.Description != "" {
+		for _, line := range strings.Split(strings.TrimSpace(sc.Description), "\n") {
+			fmt.Fprintf(&b, "# %s\n", line)
+		}
+	}
+	fmt.Fprintf(&b, "module %q {\n", sc.moduleName())
+	fmt.Fprintf(&b, "  source = %q\n\n", "../../modules/"+sc.Module)
+	writeHCLAttributes(&b, inputs, "  ", func(key string) bool { return jsonEncodedInputs[key] })
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "output %q {\n  value = module.%s\n}\n", sc.Name, sc.moduleName())
+	return []byte(b.String())
+}
+
+// hclIdentifier matches the keys that need no quotes
+var hclIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// hclExpression matches a string holding a single interpolation, rendered as the bare expression
+var hclExpression = regexp.MustCompile(`^\$\{([^{}]+)\}$`)
+
+// writeHCLAttributes writes the attributes of an object body sorted by key. Like `terraform fmt`, the equal signs
+// of consecutive single line attributes are aligned, multi-line ones are not and end the alignment group.
+func writeHCLAttributes(b *strings.Builder, values map[string]interface{}, indent string, jsonEncoded func(string) bool) {
+	type attribute struct{ key, value string }
+	var group []attribute
+	flush := func() {
+		width := 0
+		for _, a := range group {
+			width = max(width, len(a.key))
+		}
+		for _, a := range group {
+			fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, a.key, a.value)
+		}
+		group = nil
+	}
+
+	for _, key := range sortedKeys(values) {
+		name := key
+		if !hclIdentifier.MatchString(key) {
+			name = strconv.Quote(key)
+		}
+		value := hclValue(values[key], indent)
+		if jsonEncoded != nil && jsonEncoded(key) {
+			value = "jsonencode(" + value + ")"
+		}
+		if strings.Contains(value, "\n") {
+			flush()
+			fmt.Fprintf(b, "%s%s = %s\n", indent, name, value)
+			continue
+		}
+		group = append(group, attribute{name, value})
+	}
+	flush()
+}
+
+// hclValue renders a YAML or Go value as an HCL expression, indent being the indentation of its attribute
+func hclValue(value interface{}, indent string) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		if match := hclExpression.FindStringSubmatch(v); match != nil {
+			return match[1]
+		}
+		return strconv.Quote(v)
+	case bool, int, int32, int64, float64:
+		return fmt.Sprint(v)
+	case []string:
+		values := make([]interface{}, 0, len(v))
+		for _, s := range v {
+			values = append(values, s)
+		}
+		return hclValue(values, indent)
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]"
+		}
+		items := make([]string, 0, len(v))
+		multiline := false
+		for _, item := range v {
+			rendered := hclValue(item, indent+"  ")
+			items = append(items, rendered)
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				multiline = true
+			}
+		}
+		if !multiline {
+			return "[" + strings.Join(items, ", ") + "]"
+		}
+		return "[\n" + indent + "  " + strings.Join(items, ",\n"+indent+"  ") + "\n" + indent + "]"
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}"
+		}
+		var body strings.Builder
+		writeHCLAttributes(&body, v, indent+"  ", nil)
+		return "{\n" + body.String() + indent + "}"
+	}
+	return fmt.Sprintf("%q", fmt.Sprint(value))
+}
+
+// Assert checks the task definition rendered for the scenario against its expected containers.
+// All the mismatches are reported in a single failure.
+func (sc Scenario) Assert(t testing.TB, task TaskDefinitionOutput, testPrefix string) {
+	t.Helper()
+	assert.Equal(t, testPrefix+"-"+sc.Name, task.Family, "Unexpected task family name")
+
+	names := make([]string, 0, len(sc.Containers))
+	for _, container := range sc.Containers {
+		names = append(names, container.Name)
+	}
+	expectation := ecsassert.Expect(t, task.ContainerDefinitions).Containers(names...)
+
+	for _, expected := range sc.Containers {
+		c := expectation.Container(expected.Name)
+		if expected.Image != "" {
+			c.Image(expected.Image)
+		}
+		if expected.User != "" {
+			c.User(expected.User)
+		}
+		if expected.Essential != nil && *expected.Essential {
+			c.Essential()
+		} else if expected.Essential != nil {
+			c.NotEssential()
+		}
+		if expected.Command != nil {
+			c.Command(expected.Command...)
+		}
+		if expected.EntryPoint != nil {
+			c.EntryPoint(expected.EntryPoint...)
+		}
+		c.HasEnv(expected.Env).LacksEnv(expected.AbsentEnv...).HasDockerLabels(expected.DockerLabels)
+		for _, name := range sortedKeys(expected.Secrets) {
+			c.HasSecret(name, expected.Secrets[name])
+		}
+		for _, mount := range expected.Mounts {
+			mountPoint, _ := parseMount(mount)
+			c.HasMount(mountPoint)
+		}
+		for _, port := range expected.Ports {
+			mapping, _ := parsePort(port)
+			c.HasPort(mapping)
+		}
+		for _, name := range sortedKeys(expected.DependsOn) {
+			c.DependsOn(types.ContainerDependency{ContainerName: aws.String(name), Condition: types.ContainerCondition(expected.DependsOn[name])})
+		}
+		if expected.LogDriver != "" {
+			c.LogDriver(types.LogDriver(expected.LogDriver))
+		}
+		if len(expected.LogOptions) > 0 {
+			c.HasLogOptions(expected.LogOptions)
+		}
+		if expected.Firelens != "" {
+			c.Firelens(types.FirelensConfigurationType(expected.Firelens))
+		}
+		if len(expected.Capabilities) > 0 {
+			c.AddsCapabilities(expected.Capabilities...)
+		}
+	}
+	expectation.Check()
+}
@@ -0,0 +1,130 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadScenarios tests that the declared scenarios are parsed and misspelled fields rejected
+func TestLoadScenarios(t *testing.T) {
+	scenarios, err := LoadScenarios(scenariosDir, "ecs_fargate")
+	require.NoError(t, err)
+	require.NotEmpty(t, scenarios)
+	for _, scenario := range scenarios {
+		assert.Equal(t, "ecs_fargate", scenario.Module)
+		assert.NotEmpty(t, scenario.Title, "Scenario %s has no title", scenario.Name)
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "typo.yaml"), []byte("module: ecs_fargate\ncontainers:\n  - name: datadog-agent\n    enviroment: {}\n"), 0o644))
+	_, err = LoadScenarios(dir, "ecs_fargate")
+	assert.ErrorContains(t, err, "field enviroment not found")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "typo.yaml"), []byte("module: ecs_fargate\ncontainers:\n  - name: datadog-agent\n    mounts: [agent-run]\n"), 0o644))
+	_, err = LoadScenarios(dir, "ecs_fargate")
+	assert.ErrorContains(t, err, `mount "agent-run" should be volume:/container/path`)
+}
+
+// TestRenderScenarios compares the smoke test files to the ones rendered from the scenarios, run with -update to regenerate them
+func TestRenderScenarios(t *testing.T) {
+	for _, module := range []string{"ecs_fargate", "ecs_ec2"} {
+		scenarios, err := LoadScenarios(scenariosDir, module)
+		require.NoError(t, err)
+		for _, scenario := range scenarios {
+			t.Run(scenario.Name, func(t *testing.T) {
+				rendered := scenario.RenderHCL()
+				path := scenario.SmokeTestPath()
+				if *updateGolden {
+					require.NoError(t, os.WriteFile(path, rendered, 0o644))
+					t.Logf("Updated smoke test %s", path)
+					return
+				}
+				actual, err := os.ReadFile(path)
+				require.NoError(t, err, "Smoke test %s is not rendered, run the tests with -update to create it", path)
+				assert.Equal(t, string(rendered), string(actual), "Smoke test %s is out of date, run the tests with -update to regenerate it", path)
+			})
+		}
+	}
+}
+
+// TestRenderHCLAlignment tests that equal signs are aligned like terraform fmt does
+func TestRenderHCLAlignment(t *testing.T) {
+	var b strings.Builder
+	writeHCLAttributes(&b, map[string]interface{}{
+		"a":       1,
+		"bb":      "${var.bb}",
+		"c":       map[string]interface{}{"enabled": true, "x": nil},
+		"dd":      []interface{}{"one", 2},
+		"eee":     "quoted \"value\"",
+		"ffff_ff": []interface{}{map[string]interface{}{"name": "app"}},
+	}, "  ", func(key string) bool { return key == "ffff_ff" })
+
+	assert.Equal(t, `  a  = 1
+  bb = var.bb
+  c = {
+    enabled = true
+    x       = null
+  }
+  dd  = ["one", 2]
+  eee = "quoted \"value\""
+  ffff_ff = jsonencode([
+    {
+      name = "app"
+    }
+  ])
+`, b.String())
+}
+
+// TestScenarioAssert tests that a task definition matching the expected containers passes
+func TestScenarioAssert(t *testing.T) {
+	scenario := Scenario{
+		Name: "agent",
+		Containers: []ExpectedContainer{{
+			Name:      "datadog-agent",
+			Essential: aws.Bool(true),
+			Env:       map[string]string{"DD_SITE": "datadoghq.com"},
+			Ports:     []string{"8125:8125/udp"},
+			Mounts:    []string{"agent-run:/opt/datadog-agent/run:ro"},
+			DependsOn: map[string]string{"init-volume": "SUCCESS"},
+		}},
+	}
+	scenario.Assert(t, TaskDefinitionOutput{
+		Family: fmt.Sprintf("%s-agent", defaultTestPrefix),
+		ContainerDefinitions: []types.ContainerDefinition{{
+			Name:         aws.String("datadog-agent"),
+			Essential:    aws.Bool(true),
+			Environment:  []types.KeyValuePair{{Name: aws.String("DD_SITE"), Value: aws.String("datadoghq.com")}},
+			PortMappings: []types.PortMapping{{ContainerPort: aws.Int32(8125), HostPort: aws.Int32(8125), Protocol: types.TransportProtocolUdp}},
+			MountPoints:  []types.MountPoint{{SourceVolume: aws.String("agent-run"), ContainerPath: aws.String("/opt/datadog-agent/run"), ReadOnly: aws.Bool(true)}},
+			DependsOn:    []types.ContainerDependency{{ContainerName: aws.String("init-volume"), Condition: types.ContainerConditionSuccess}},
+		}},
+	}, defaultTestPrefix)
+}
+
+// TestDeclaredScenarios runs the assertions of the scenarios declared under tests/scenarios for the module
+func (s *ecsSuite[T]) TestDeclaredScenarios() {
+	log.Println("TestDeclaredScenarios: Running test...")
+
+	scenarios, err := LoadScenarios(scenariosDir, s.smokeTest)
+	s.Require().NoError(err)
+	for _, scenario := range scenarios {
+		s.Run(scenario.Name, func() {
+			task := s.taskOutput(scenario.Name)
+			scenario.Assert(s.T(), task.Definition(), s.testPrefix)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+)
+
+// ShowState runs `terraform show -json` on the state of a root module and returns the parsed state
+func ShowState(t *testing.T, options *terraform.Options) *tfjson.State {
+	stateOptions := *options
+	stateOptions.PlanFilePath = ""
+	output := terraform.Show(t, &stateOptions)
+
+	var state tfjson.State
+	require.NoError(t, json.Unmarshal([]byte(output), &state), "Failed to parse terraform show -json")
+	return &state
+}
+
+// StateResources returns the managed resources of a type in a state, by address.
+// Only resources under the module address prefix are returned (eg. `module.dd_task_all_ecs_inputs.`), all of them if empty.
+func StateResources(state *tfjson.State, resourceType string, modulePrefix string) map[string]*tfjson.StateResource {
+	resources := map[string]*tfjson.StateResource{}
+	if state == nil || state.Values == nil {
+		return resources
+	}
+	collectResources(state.Values.RootModule, resourceType, modulePrefix, resources)
+	return resources
+}
+
+// PlannedResources returns the managed resources of a type planned by a `terraform show -json` plan, by address.
+// Only resources under the module address prefix are returned, all of them if empty.
+func PlannedResources(plan *terraform.PlanStruct, resourceType string, modulePrefix string) map[string]*tfjson.StateResource {
+	resources := map[string]*tfjson.StateResource{}
+	for address, resource := range plan.ResourcePlannedValuesMap {
+		if resource.Mode == tfjson.ManagedResourceMode && resource.Type == resourceType && strings.HasPrefix(address, modulePrefix) {
+			resources[address] = resource
+		}
+	}
+	return resources
+}
+
+func collectResources(module *tfjson.StateModule, resourceType string, modulePrefix string, resources map[string]*tfjson.StateResource) {
+	if module == nil {
+		return
+	}
+	for _, resource := range module.Resources {
+		if resource.Mode == tfjson.ManagedResourceMode && resource.Type == resourceType && strings.HasPrefix(resource.Address, modulePrefix) {
+			resources[resource.Address] = resource
+		}
+	}
+	for _, child := range module.ChildModules {
+		collectResources(child, resourceType, modulePrefix, resources)
+	}
+}
+
+// ResourceAddresses returns the sorted addresses of resources, without the module prefix
+func ResourceAddresses(resources map[string]*tfjson.StateResource, modulePrefix string) []string {
+	addresses := make([]string, 0, len(resources))
+	for address := range resources {
+		addresses = append(addresses, strings.TrimPrefix(address, modulePrefix))
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// ResourceAttribute returns a string attribute of a resource, empty if missing or unknown
+func ResourceAttribute(resource *tfjson.StateResource, name string) string {
+	value, _ := resource.AttributeValues[name].(string)
+	return value
+}
+
+// IAMPolicyDocument is an IAM policy as rendered by aws_iam_policy_document
+type IAMPolicyDocument struct {
+	Version   string               `json:"Version"`
+	Statement []IAMPolicyStatement `json:"Statement"`
+}
+
+// IAMPolicyStatement is a statement of an IAM policy. Actions and resources may be rendered as a string or a list.
+type IAMPolicyStatement struct {
+	Effect   string        `json:"Effect"`
+	Action   iamStringList `json:"Action"`
+	Resource iamStringList `json:"Resource"`
+}
+
+// iamStringList decodes an IAM policy element given as a single string or a list of strings
+type iamStringList []string
+
+func (l *iamStringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = iamStringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("expected a string or a list of strings: %w", err)
+	}
+	*l = list
+	return nil
+}
+
+// DecodeIAMPolicyDocument parses the policy JSON of an aws_iam_policy
+func DecodeIAMPolicyDocument(policy string) (IAMPolicyDocument, error) {
+	var document IAMPolicyDocument
+	err := json.Unmarshal([]byte(policy), &document)
+	return document, err
+}
+
+// Grants returns the allowed actions by resource, sorted
+func (d IAMPolicyDocument) Grants() map[string][]string {
+	grants := map[string][]string{}
+	for _, statement := range d.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		for _, resource := range statement.Resource {
+			grants[resource] = append(grants[resource], statement.Action...)
+		}
+	}
+	for resource := range grants {
+		sort.Strings(grants[resource])
+	}
+	return grants
+}
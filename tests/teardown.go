@@ -0,0 +1,231 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	terratesting "github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// destroyRetries is the number of times a failed destroy is retried, eg. while an interrupted apply still holds the state lock
+const destroyRetries = 2
+
+// destroyRetryDelay is the time between two destroy attempts
+var destroyRetryDelay = 15 * time.Second
+
+// LeftoversFile is written to the workspace of a scenario whose destroy failed, listing the resources still in its state
+const LeftoversFile = "leftovers.json"
+
+// Leftovers is the manifest of the resources a failed destroy left behind, for the sweeper or a human to delete
+type Leftovers struct {
+	Scenario     string             `json:"scenario"`
+	TerraformDir string             `json:"terraform_dir"`
+	Error        string             `json:"error"`
+	Resources    []LeftoverResource `json:"resources"`
+}
+
+// LeftoverResource is a managed resource still in the state after a failed destroy
+type LeftoverResource struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	ARN     string `json:"arn,omitempty"`
+}
+
+// rawState is the subset of the terraform.tfstate format the leftovers are read from
+type rawState struct {
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   interface{} `json:"index_key"`
+			Attributes struct {
+				ID  string `json:"id"`
+				ARN string `json:"arn"`
+			} `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// StateLeftovers returns the managed resources of a local state file, sorted by address.
+// The file is read directly rather than with `terraform show`, which a failed destroy may not be able to run.
+func StateLeftovers(statePath string) ([]LeftoverResource, error) {
+	data, err := os.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state rawState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", statePath, err)
+	}
+
+	var resources []LeftoverResource
+	for _, resource := range state.Resources {
+		if resource.Mode != "managed" {
+			continue
+		}
+		address := resource.Type + "." + resource.Name
+		if resource.Module != "" {
+			address = resource.Module + "." + address
+		}
+		for _, instance := range resource.Instances {
+			instanceAddress := address
+			switch key := instance.IndexKey.(type) {
+			case float64:
+				instanceAddress = fmt.Sprintf("%s[%d]", address, int(key))
+			case string:
+				instanceAddress = fmt.Sprintf("%s[%q]", address, key)
+			}
+			resources = append(resources, LeftoverResource{
+				Address: instanceAddress,
+				Type:    resource.Type,
+				ID:      instance.Attributes.ID,
+				ARN:     instance.Attributes.ARN,
+			})
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Address < resources[j].Address })
+	return resources, nil
+}
+
+// Destroy destroys the resources of the workspace, retrying a failed destroy.
+// When every attempt fails, the resources still in its state are listed in LeftoversFile in the workspace.
+func (w *ScenarioWorkspace) Destroy(t terratesting.TestingT) error {
+	_, err := retry.DoWithRetryE(t, "Destroying scenario "+w.Scenario, destroyRetries, destroyRetryDelay, func() (string, error) {
+		return terraform.DestroyE(t, w.Options)
+	})
+	if err == nil {
+		return nil
+	}
+
+	resources, stateErr := StateLeftovers(filepath.Join(w.Options.TerraformDir, "terraform.tfstate"))
+	if stateErr != nil {
+		return fmt.Errorf("%w, and its leftovers could not be read: %v", err, stateErr)
+	}
+	leftovers := Leftovers{Scenario: w.Scenario, TerraformDir: w.Options.TerraformDir, Error: err.Error(), Resources: resources}
+	data, marshalErr := json.MarshalIndent(leftovers, "", "  ")
+	if marshalErr != nil {
+		return fmt.Errorf("%w, and its leftovers could not be written: %v", err, marshalErr)
+	}
+	path := w.LeftoversPath()
+	if writeErr := os.WriteFile(path, data, 0o644); writeErr != nil {
+		return fmt.Errorf("%w, and its leftovers could not be written: %v", err, writeErr)
+	}
+	return fmt.Errorf("%w, %d resources are left, listed in %s", err, len(resources), path)
+}
+
+// LeftoversPath is where Destroy lists the resources it could not destroy
+func (w *ScenarioWorkspace) LeftoversPath() string {
+	return filepath.Join(w.Dir, LeftoversFile)
+}
+
+// Applied reports whether the workspace may hold resources: its apply started in this run, or it kept
+// the state of a previous run
+func (w *ScenarioWorkspace) Applied() bool {
+	if w.applyStarted.Load() {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(w.Options.TerraformDir, "terraform.tfstate"))
+	return err == nil
+}
+
+// DestroyOnSignal destroys the workspaces, then exits, when the process is interrupted (SIGINT) or terminated (SIGTERM)
+// before the suite tears them down. Terraform receives the signals too and stops the apply or plan it runs, releasing the state.
+// The returned function stops trapping the signals, once the suite has torn down its workspaces.
+func DestroyOnSignal(workspaces []*ScenarioWorkspace) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		var sig os.Signal
+		select {
+		case sig = <-signals:
+		case <-done:
+			return
+		}
+		log.Printf("Received %s, destroying the resources of %d scenarios before exiting...", sig, len(workspaces))
+		// Further signals are ignored until the resources are destroyed, stopping the destroy would leak them
+		go func() {
+			for sig := range signals {
+				log.Printf("Received %s, still destroying the resources...", sig)
+			}
+		}()
+
+		destroyWorkspaces(workspaces)
+		os.Exit(128 + int(sig.(syscall.Signal)))
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// destroyWorkspaces destroys the applied workspaces in parallel, then removes them, logging the failures.
+// It runs outside of any test, which may have completed by then, so terraform logs through a signalT.
+func destroyWorkspaces(workspaces []*ScenarioWorkspace) {
+	var wg sync.WaitGroup
+	for _, workspace := range workspaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if workspace.Applied() {
+				if err := workspace.Destroy(signalT{name: "destroy " + workspace.Scenario}); err != nil {
+					log.Printf("Failed to destroy scenario %s: %v", workspace.Scenario, err)
+					return
+				}
+			}
+			if err := os.RemoveAll(workspace.Dir); err != nil {
+				log.Printf("Failed to remove the workspace of scenario %s: %v", workspace.Scenario, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// signalT is the terratest TestingT of the destroys run on a signal, logging its failures with the standard logger
+type signalT struct {
+	name string
+}
+
+func (t signalT) Name() string { return t.name }
+
+func (t signalT) Fail() {}
+
+func (t signalT) FailNow() { runtime.Goexit() }
+
+func (t signalT) Error(args ...interface{}) { log.Print(args...) }
+
+func (t signalT) Errorf(format string, args ...interface{}) { log.Printf(format, args...) }
+
+func (t signalT) Fatal(args ...interface{}) {
+	log.Print(args...)
+	t.FailNow()
+}
+
+func (t signalT) Fatalf(format string, args ...interface{}) {
+	log.Printf(format, args...)
+	t.FailNow()
+}
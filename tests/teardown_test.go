@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leftoverState is a local state holding a module task definition, a counted role and a data source
+const leftoverState = `{
+  "version": 4,
+  "resources": [
+    {
+      "module": "module.dd_task_cws_only",
+      "mode": "managed",
+      "type": "aws_ecs_task_definition",
+      "name": "this",
+      "instances": [{"attributes": {"id": "terraform-test-cws-only", "arn": "arn:aws:ecs:us-east-1:123456789012:task-definition/terraform-test-cws-only:1"}}]
+    },
+    {
+      "module": "module.dd_task_cws_only",
+      "mode": "managed",
+      "type": "aws_iam_role",
+      "name": "new_ecs_task_execution_role",
+      "instances": [{"index_key": 0, "attributes": {"id": "terraform-test-cws-only-ecs-task-exec-role", "arn": "arn:aws:iam::123456789012:role/terraform-test-cws-only-ecs-task-exec-role"}}]
+    },
+    {
+      "module": "module.dd_task_cws_only",
+      "mode": "data",
+      "type": "aws_iam_policy_document",
+      "name": "dd_secret_access",
+      "instances": [{"index_key": 0, "attributes": {"id": "1234"}}]
+    }
+  ]
+}`
+
+// TestDestroyWritesLeftovers tests that a destroy failing on every attempt lists the managed resources left in the state
+func TestDestroyWritesLeftovers(t *testing.T) {
+	previousDelay := destroyRetryDelay
+	destroyRetryDelay = 0
+	t.Cleanup(func() { destroyRetryDelay = previousDelay })
+
+	dir := t.TempDir()
+	workspace := &ScenarioWorkspace{
+		Scenario: "cws-only",
+		Dir:      dir,
+		// `false` fails like a destroy that cannot reach AWS
+		Options: &terraform.Options{TerraformDir: dir, TerraformBinary: "false"},
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "terraform.tfstate"), []byte(leftoverState), 0o644))
+
+	err := workspace.Destroy(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 resources are left, listed in "+workspace.LeftoversPath())
+
+	data, err := os.ReadFile(workspace.LeftoversPath())
+	require.NoError(t, err)
+	var leftovers Leftovers
+	require.NoError(t, json.Unmarshal(data, &leftovers))
+	assert.Equal(t, "cws-only", leftovers.Scenario)
+	assert.NotEmpty(t, leftovers.Error)
+	assert.Equal(t, []LeftoverResource{
+		{
+			Address: "module.dd_task_cws_only.aws_ecs_task_definition.this",
+			Type:    "aws_ecs_task_definition",
+			ID:      "terraform-test-cws-only",
+			ARN:     "arn:aws:ecs:us-east-1:123456789012:task-definition/terraform-test-cws-only:1",
+		},
+		{
+			Address: "module.dd_task_cws_only.aws_iam_role.new_ecs_task_execution_role[0]",
+			Type:    "aws_iam_role",
+			ID:      "terraform-test-cws-only-ecs-task-exec-role",
+			ARN:     "arn:aws:iam::123456789012:role/terraform-test-cws-only-ecs-task-exec-role",
+		},
+	}, leftovers.Resources)
+}
+
+// TestDestroyWorkspacesSkipsUnapplied tests that the signal path only destroys the workspaces that may hold resources
+func TestDestroyWorkspacesSkipsUnapplied(t *testing.T) {
+	previousDelay := destroyRetryDelay
+	destroyRetryDelay = 0
+	t.Cleanup(func() { destroyRetryDelay = previousDelay })
+
+	newWorkspace := func(scenario string) *ScenarioWorkspace {
+		dir := filepath.Join(t.TempDir(), scenario)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		// `false` fails like a destroy that cannot reach AWS
+		return &ScenarioWorkspace{Scenario: scenario, Dir: dir, Options: &terraform.Options{TerraformDir: dir, TerraformBinary: "false"}}
+	}
+	unapplied := newWorkspace("all-null")
+	applied := newWorkspace("cws-only")
+	require.NoError(t, os.WriteFile(filepath.Join(applied.Dir, "terraform.tfstate"), []byte(leftoverState), 0o644))
+	interrupted := newWorkspace("logging-only")
+	interrupted.applyStarted.Store(true)
+
+	assert.False(t, unapplied.Applied())
+	assert.True(t, applied.Applied())
+	assert.True(t, interrupted.Applied())
+
+	destroyWorkspaces([]*ScenarioWorkspace{unapplied, applied, interrupted})
+	assert.NoDirExists(t, unapplied.Dir, "A workspace never applied should be removed without a destroy")
+	assert.FileExists(t, applied.LeftoversPath())
+	assert.FileExists(t, interrupted.LeftoversPath())
+}
@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// UpgradeEnvVar enables the upgrade test (eg. `TERRAFORM_UPGRADE=true`), which applies real resources.
+// Only `make test-upgrade` sets it, so a plain `go test ./tests` never runs it.
+const UpgradeEnvVar = "TERRAFORM_UPGRADE"
+
+// IsUpgrade reports whether the upgrade test should run
+func IsUpgrade() bool {
+	upgrade, err := strconv.ParseBool(os.Getenv(UpgradeEnvVar))
+	return err == nil && upgrade
+}
+
+// UpgradeFromEnvVar overrides the git ref the upgrade test applies first (default: the previous release tag)
+const UpgradeFromEnvVar = "TERRAFORM_UPGRADE_FROM"
+
+// upgradeReplaceableTypes are the resource types an upgrade may destroy and recreate.
+// A new task definition revision is expected when the rendered containers change.
+var upgradeReplaceableTypes = map[string]bool{
+	"aws_ecs_task_definition": true,
+}
+
+// PreviousReleaseTag returns the most recent tag before the current commit of the git repository at repoDir.
+// It returns an empty string when the history has no tag.
+func PreviousReleaseTag(repoDir string) (string, error) {
+	command := exec.Command("git", "describe", "--tags", "--abbrev=0", "HEAD^")
+	command.Dir = repoDir
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+	output, err := command.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "No names found") || strings.Contains(stderr.String(), "No tags can describe") {
+			return "", nil
+		}
+		return "", fmt.Errorf("git describe: %w: %s", err, stderr.String())
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ExportModules extracts the modules directory of a git ref into destination, and returns the extracted modules directory
+func ExportModules(repoDir string, ref string, destination string) (string, error) {
+	command := exec.Command("git", "archive", "--format=tar", ref, "modules")
+	command.Dir = repoDir
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+	archive, err := command.Output()
+	if err != nil {
+		return "", fmt.Errorf("git archive %s: %w: %s", ref, err, stderr.String())
+	}
+
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		path := filepath.Join(destination, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(destination)+string(os.PathSeparator)) {
+			return "", fmt.Errorf("unexpected path %s in archive", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return "", err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return "", err
+			}
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				return "", err
+			}
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				return "", err
+			}
+		}
+	}
+	return filepath.Join(destination, "modules"), nil
+}
+
+// RenderModuleSource renders the main.tf.tmpl of a root module directory to main.tf, using the modules of modulesDir
+func RenderModuleSource(dir string, modulesDir string) error {
+	tmpl, err := template.ParseFiles(filepath.Join(dir, "main.tf.tmpl"))
+	if err != nil {
+		return err
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, struct{ ModulesDir string }{filepath.ToSlash(modulesDir)}); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "main.tf"), rendered.Bytes(), 0o644)
+}
+
+// UnexpectedUpgradeChanges describes the planned changes an upgrade must not make:
+// destroying or replacing any resource other than the replaceable types
+func UnexpectedUpgradeChanges(changes []*tfjson.ResourceChange) []string {
+	var unexpected []string
+	for _, change := range changes {
+		if change.Change == nil || change.Mode != tfjson.ManagedResourceMode || upgradeReplaceableTypes[change.Type] {
+			continue
+		}
+		actions := change.Change.Actions
+		if actions.Delete() || actions.Replace() || actions.Forget() {
+			var names []string
+			for _, action := range actions {
+				names = append(names, string(action))
+			}
+			unexpected = append(unexpected, fmt.Sprintf("%s: %s", change.Address, strings.Join(names, ", ")))
+		}
+	}
+	sort.Strings(unexpected)
+	return unexpected
+}
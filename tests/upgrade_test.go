@@ -0,0 +1,103 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpgradeFromPreviousRelease applies the upgrade_path fixture with the modules of the previous release,
+// switches it to the modules of the working tree and checks the upgrade plan only replaces task definitions
+func TestUpgradeFromPreviousRelease(t *testing.T) {
+	if !IsUpgrade() {
+		t.Skipf("Skipping the upgrade path, set %s=true or run make test-upgrade", UpgradeEnvVar)
+	}
+	if IsPlanOnly() {
+		t.Skip("The upgrade path applies the previous release, which plan-only mode does not allow")
+	}
+
+	ref := os.Getenv(UpgradeFromEnvVar)
+	if ref == "" {
+		var err error
+		ref, err = PreviousReleaseTag("..")
+		require.NoError(t, err)
+		if ref == "" {
+			t.Skipf("No release tag found in the git history, set %s to the ref to upgrade from", UpgradeFromEnvVar)
+		}
+	}
+	t.Logf("Upgrading from %s", ref)
+
+	dir, err := files.CopyTerraformFolderToDest("fixtures/upgrade_path", t.TempDir(), "upgrade")
+	require.NoError(t, err)
+	previousModules, err := ExportModules("..", ref, t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, RenderModuleSource(dir, previousModules))
+
+	testPrefix := defaultTestPrefix
+	if ciJobID := os.Getenv("CI_JOB_ID"); ciJobID != "" {
+		testPrefix = testPrefix + "-" + ciJobID
+	}
+	options := &terraform.Options{
+		TerraformDir: dir,
+		Vars: map[string]interface{}{
+			"test_prefix": testPrefix,
+		},
+		RetryableTerraformErrors: map[string]string{
+			"couldn't find resource": "terratest could not find the resource. check for access denied errors in cloudtrail",
+		},
+	}
+	if IsFakeAWS() {
+		server := StartFakeAWS(options)
+		defer server.Close()
+	}
+	defer terraform.Destroy(t, options)
+	terraform.InitAndApply(t, options)
+
+	// Changing the module source requires a new init
+	workingModules, err := filepath.Abs(filepath.Join("..", "modules"))
+	require.NoError(t, err)
+	require.NoError(t, RenderModuleSource(dir, workingModules))
+	terraform.Init(t, options)
+
+	planOptions := *options
+	planOptions.PlanFilePath = filepath.Join(t.TempDir(), "plan.out")
+	terraform.Plan(t, &planOptions)
+	plan := terraform.ShowWithStruct(t, &planOptions)
+
+	changes := describeResourceChanges(plan.RawPlan.ResourceChanges)
+	t.Logf("Changes planned by the upgrade from %s:\n%s", ref, strings.Join(changes, "\n"))
+	unexpected := UnexpectedUpgradeChanges(plan.RawPlan.ResourceChanges)
+	assert.Empty(t, unexpected, "Upgrading from %s should only replace task definitions:\n%s", ref, strings.Join(unexpected, "\n"))
+}
+
+// TestUnexpectedUpgradeChanges tests that only destroying resources other than task definitions is reported
+func TestUnexpectedUpgradeChanges(t *testing.T) {
+	change := func(address, resourceType string, mode tfjson.ResourceMode, actions ...tfjson.Action) *tfjson.ResourceChange {
+		return &tfjson.ResourceChange{Address: address, Type: resourceType, Mode: mode, Change: &tfjson.Change{Actions: actions}}
+	}
+	changes := []*tfjson.ResourceChange{
+		change("module.dd_task_upgrade.aws_ecs_task_definition.this", "aws_ecs_task_definition", tfjson.ManagedResourceMode, tfjson.ActionCreate, tfjson.ActionDelete),
+		change("module.dd_task_upgrade.aws_iam_role.new_ecs_task_role[0]", "aws_iam_role", tfjson.ManagedResourceMode, tfjson.ActionUpdate),
+		change("module.dd_task_upgrade.aws_iam_policy.dd_secret_access[0]", "aws_iam_policy", tfjson.ManagedResourceMode, tfjson.ActionDelete, tfjson.ActionCreate),
+		change("module.dd_task_upgrade.aws_iam_role_policy_attachment.existing_role_dd_secret[0]", "aws_iam_role_policy_attachment", tfjson.ManagedResourceMode, tfjson.ActionDelete),
+		change("module.dd_task_upgrade.aws_iam_role.new_ecs_task_execution_role[0]", "aws_iam_role", tfjson.ManagedResourceMode, tfjson.ActionCreate),
+		change("module.dd_task_upgrade.data.aws_iam_policy_document.dd_secret_access[0]", "aws_iam_policy_document", tfjson.DataResourceMode, tfjson.ActionRead),
+	}
+
+	assert.Equal(t, []string{
+		"module.dd_task_upgrade.aws_iam_policy.dd_secret_access[0]: delete, create",
+		"module.dd_task_upgrade.aws_iam_role_policy_attachment.existing_role_dd_secret[0]: delete",
+	}, UnexpectedUpgradeChanges(changes))
+}
@@ -6,11 +6,9 @@
 package test
 
 import (
-	"encoding/json"
 	"log"
 
-	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
-	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
 )
 
 // TestUSTDockerLabels tests that UST docker labels are propagated to all container definitions
@@ -19,13 +17,11 @@ func (s *ECSFargateSuite) TestUSTDockerLabels() {
 	log.Println("TestUSTDockerLabels: Running test...")
 
 	// Retrieve the task output for the "ust-docker-labels" module
-	var containers []types.ContainerDefinition
-	task := terraform.OutputMap(s.T(), s.terraformOptions, "ust-docker-labels")
-	s.Equal(s.testPrefix+"-ust-docker-labels", task["family"], "Unexpected task family name")
+	task := s.taskOutput("ust-docker-labels")
+	s.Equal(s.testPrefix+"-ust-docker-labels", task.Family, "Unexpected task family name")
 
-	err := json.Unmarshal([]byte(task["container_definitions"]), &containers)
-	s.NoError(err, "Failed to parse container definitions")
-	s.Equal(5, len(containers), "Expected 4 containers in the task definition (1 app container + 3 agent sidecar)")
+	containers := task.ContainerDefinitions
+	s.Equal(5, len(containers), "Expected 5 containers in the task definition")
 
 	// Expected UST docker labels that should be present on all application containers
 	expectedUSTLabels := map[string]string{
@@ -34,21 +30,22 @@ func (s *ECSFargateSuite) TestUSTDockerLabels() {
 		"com.datadoghq.tags.version": "1.2.3",
 	}
 
-	dummyApp, found := GetContainer(containers, "dummy-app")
-	s.True(found, "Container dummy-app not found in definitions")
-	AssertDockerLabels(s.T(), dummyApp, expectedUSTLabels)
-
 	// Expect UST docker labels to be present on all Datadog containers with
 	// overwritten labels when UST docker labels are specified.
-	datadogContainers := []string{"datadog-agent", "datadog-log-router", "cws-instrumentation-init"}
 	expectedAgentUSTLabels := map[string]string{
 		"com.datadoghq.tags.service": "docker-agent-service",
 		"com.datadoghq.tags.env":     "agent-dev",
 		"com.datadoghq.tags.version": "v1.2.3",
 	}
-	for _, containerName := range datadogContainers {
-		container, found := GetContainer(containers, containerName)
-		s.True(found, "Container %s not found in definitions", containerName)
-		AssertDockerLabels(s.T(), container, expectedAgentUSTLabels)
-	}
+
+	ecsassert.Expect(s.T(), containers).
+		Container("dummy-app").
+		HasDockerLabels(expectedUSTLabels).
+		Container("datadog-agent").
+		HasDockerLabels(expectedAgentUSTLabels).
+		Container("datadog-log-router").
+		HasDockerLabels(expectedAgentUSTLabels).
+		Container("cws-instrumentation-init").
+		HasDockerLabels(expectedAgentUSTLabels).
+		Check()
 }
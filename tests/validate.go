@@ -0,0 +1,106 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// Rules checked by ValidateTaskDefinition
+const (
+	RuleDependsOnTarget     = "depends-on-target"
+	RuleMountPointVolume    = "mount-point-volume"
+	RuleUniqueContainerName = "unique-container-name"
+	RuleUniqueEnvVar        = "unique-env-var"
+	RuleEssentialContainer  = "essential-container"
+	RuleFirelensLogRouter   = "firelens-log-router"
+)
+
+// Violation is a rule a task definition does not satisfy. Container is empty for task-wide rules.
+type Violation struct {
+	Rule      string
+	Container string
+	Message   string
+}
+
+func (v Violation) String() string {
+	if v.Container == "" {
+		return fmt.Sprintf("[%s] %s", v.Rule, v.Message)
+	}
+	return fmt.Sprintf("[%s] container %s: %s", v.Rule, v.Container, v.Message)
+}
+
+// ValidateTaskDefinition returns the violations of the rules every task definition rendered by the modules must satisfy
+func ValidateTaskDefinition(task TaskDefinitionOutput) []Violation {
+	var violations []Violation
+	containers := task.ContainerDefinitions
+
+	names := map[string]int{}
+	for _, container := range containers {
+		names[aws.ToString(container.Name)]++
+	}
+	volumes := map[string]bool{}
+	for _, volume := range task.Volumes {
+		volumes[aws.ToString(volume.Name)] = true
+	}
+
+	hasEssential := false
+	hasFirelensRouter := false
+	for _, container := range containers {
+		// ECS considers containers without the essential parameter essential
+		if container.Essential == nil || *container.Essential {
+			hasEssential = true
+		}
+		if container.FirelensConfiguration != nil {
+			hasFirelensRouter = true
+		}
+	}
+
+	reportedNames := map[string]bool{}
+	for _, container := range containers {
+		name := aws.ToString(container.Name)
+
+		if names[name] > 1 && !reportedNames[name] {
+			reportedNames[name] = true
+			violations = append(violations, Violation{RuleUniqueContainerName, name, fmt.Sprintf("defined %d times", names[name])})
+		}
+
+		for _, dependency := range container.DependsOn {
+			target := aws.ToString(dependency.ContainerName)
+			if names[target] == 0 {
+				violations = append(violations, Violation{RuleDependsOnTarget, name, fmt.Sprintf("depends on missing container %s", target)})
+			}
+		}
+
+		for _, mountPoint := range container.MountPoints {
+			source := aws.ToString(mountPoint.SourceVolume)
+			if !volumes[source] {
+				violations = append(violations, Violation{RuleMountPointVolume, name, fmt.Sprintf("mounts missing volume %s at %s", source, aws.ToString(mountPoint.ContainerPath))})
+			}
+		}
+
+		envNames := map[string]bool{}
+		for _, env := range container.Environment {
+			envName := aws.ToString(env.Name)
+			if envNames[envName] {
+				violations = append(violations, Violation{RuleUniqueEnvVar, name, fmt.Sprintf("sets environment variable %s more than once", envName)})
+			}
+			envNames[envName] = true
+		}
+
+		if config := container.LogConfiguration; config != nil && config.LogDriver == types.LogDriverAwsfirelens && !hasFirelensRouter {
+			violations = append(violations, Violation{RuleFirelensLogRouter, name, "logs to awsfirelens without a firelens log router in the task"})
+		}
+	}
+
+	if len(containers) > 0 && !hasEssential {
+		violations = append(violations, Violation{RuleEssentialContainer, "", "no container is essential"})
+	}
+	return violations
+}
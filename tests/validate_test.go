@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"log"
+	"testing"
+
+	"github.com/DataDog/terraform-aws-ecs-datadog/tests/ecsassert"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateTaskDefinition tests that every rule reports its violations
+func TestValidateTaskDefinition(t *testing.T) {
+	valid := TaskDefinitionOutput{
+		ContainerDefinitions: []types.ContainerDefinition{
+			{
+				Name:        aws.String("datadog-agent"),
+				Essential:   aws.Bool(true),
+				MountPoints: []types.MountPoint{ecsassert.MountDdSocket},
+			},
+			{
+				Name:                  aws.String("datadog-log-router"),
+				Essential:             aws.Bool(false),
+				FirelensConfiguration: &types.FirelensConfiguration{Type: types.FirelensConfigurationTypeFluentbit},
+			},
+			{
+				Name:             aws.String("app"),
+				Essential:        aws.Bool(false),
+				DependsOn:        []types.ContainerDependency{ecsassert.DependencyAgent, ecsassert.DependencyLogRouter},
+				LogConfiguration: &types.LogConfiguration{LogDriver: types.LogDriverAwsfirelens},
+			},
+		},
+		Volumes: []types.Volume{{Name: aws.String("dd-sockets")}},
+	}
+	assert.Empty(t, ValidateTaskDefinition(valid))
+
+	invalid := TaskDefinitionOutput{
+		ContainerDefinitions: []types.ContainerDefinition{
+			{
+				Name:        aws.String("datadog-agent"),
+				Essential:   aws.Bool(false),
+				MountPoints: []types.MountPoint{ecsassert.MountDdSocket},
+				Environment: []types.KeyValuePair{
+					{Name: aws.String("DD_SITE"), Value: aws.String("datadoghq.com")},
+					{Name: aws.String("DD_SITE"), Value: aws.String("datadoghq.eu")},
+				},
+			},
+			{
+				Name:             aws.String("app"),
+				Essential:        aws.Bool(false),
+				DependsOn:        []types.ContainerDependency{ecsassert.DependencyCWS},
+				LogConfiguration: &types.LogConfiguration{LogDriver: types.LogDriverAwsfirelens},
+			},
+			{Name: aws.String("app"), Essential: aws.Bool(false)},
+		},
+	}
+	assert.Equal(t, []Violation{
+		{RuleMountPointVolume, "datadog-agent", "mounts missing volume dd-sockets at /var/run/datadog"},
+		{RuleUniqueEnvVar, "datadog-agent", "sets environment variable DD_SITE more than once"},
+		{RuleUniqueContainerName, "app", "defined 2 times"},
+		{RuleDependsOnTarget, "app", "depends on missing container cws-instrumentation-init"},
+		{RuleFirelensLogRouter, "app", "logs to awsfirelens without a firelens log router in the task"},
+		{RuleEssentialContainer, "", "no container is essential"},
+	}, ValidateTaskDefinition(invalid))
+}
+
+// TestValidateAllOutputs runs the baseline checks and the RegisterTaskDefinition rules on every
+// task definition output of the smoke tests, including those no test reads
+func (s *ecsSuite[T]) TestValidateAllOutputs() {
+	log.Println("TestValidateAllOutputs: Running test...")
+
+	for _, key := range TaskOutputKeys(s.outputs) {
+		s.Run(key, func() {
+			task, err := s.outputs.Get(key)
+			s.Require().NoError(err, "Failed to read the %s output", key)
+			AssertBaseline(s.T(), task.Definition())
+			AssertValidRegisterInput(s.T(), task.RegisterInput())
+		})
+	}
+}
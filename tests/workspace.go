@@ -0,0 +1,244 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	test_structure "github.com/gruntwork-io/terratest/modules/test-structure"
+	"github.com/stretchr/testify/require"
+)
+
+// ScenariosEnvVar restricts the suites to a comma-separated list of smoke test scenarios (eg. `TERRAFORM_SCENARIOS=cws-only`).
+// A scenario is named after its file in the smoke test directory.
+const ScenariosEnvVar = "TERRAFORM_SCENARIOS"
+
+// workspacesDir holds the working directory of every scenario, kept between runs when its teardown is skipped
+const workspacesDir = ".workspaces"
+
+// smokeSharedFiles are the files of a smoke test directory every scenario needs
+var smokeSharedFiles = map[string]bool{
+	"provider.tf":  true,
+	"variables.tf": true,
+	"versions.tf":  true,
+}
+
+// ScenarioWorkspace is the working directory of a single smoke test scenario, with its own terraform state
+type ScenarioWorkspace struct {
+	Scenario string
+	Dir      string
+	Options  *terraform.Options
+
+	// applyStarted is set once the setup of this run starts applying the scenario
+	applyStarted atomic.Bool
+}
+
+// scenarioOutputs are the outputs of a scenario saved by its setup stage, for the validate stage to read
+type scenarioOutputs struct {
+	Outputs map[string]json.RawMessage `json:"outputs"`
+	Errors  map[string]string          `json:"errors"`
+}
+
+// SmokeScenarios lists the scenarios of a smoke test directory: every terraform file but the shared ones
+func SmokeScenarios(smokeDir string) ([]string, error) {
+	entries, err := os.ReadDir(smokeDir)
+	if err != nil {
+		return nil, err
+	}
+	var scenarios []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".tf") || smokeSharedFiles[name] {
+			continue
+		}
+		scenarios = append(scenarios, strings.TrimSuffix(name, ".tf"))
+	}
+	return scenarios, nil
+}
+
+// ScenariosSelected reports whether ScenariosEnvVar restricts the scenarios to run
+func ScenariosSelected() bool {
+	return strings.TrimSpace(os.Getenv(ScenariosEnvVar)) != ""
+}
+
+// SelectScenarios returns the scenarios listed in ScenariosEnvVar, or all of them when it is not set
+func SelectScenarios(scenarios []string) ([]string, error) {
+	if !ScenariosSelected() {
+		return scenarios, nil
+	}
+	var selected, unknown []string
+	for _, name := range strings.Split(os.Getenv(ScenariosEnvVar), ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case slices.Contains(scenarios, name):
+			selected = append(selected, name)
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown scenarios %s in %s, expected some of %s", strings.Join(unknown, ", "), ScenariosEnvVar, strings.Join(scenarios, ", "))
+	}
+	return selected, nil
+}
+
+// NewScenarioWorkspaces returns a workspace per selected scenario of a smoke test directory.
+// Each workspace runs terraform with a copy of the options in its own directory under tests/.workspaces.
+func NewScenarioWorkspaces(t *testing.T, options *terraform.Options) []*ScenarioWorkspace {
+	scenarios, err := SmokeScenarios(options.TerraformDir)
+	require.NoError(t, err)
+	scenarios, err = SelectScenarios(scenarios)
+	require.NoError(t, err)
+
+	smokeName := filepath.Base(options.TerraformDir)
+	var workspaces []*ScenarioWorkspace
+	for _, scenario := range scenarios {
+		workspaceOptions := *options
+		workspaceOptions.Vars = make(map[string]interface{}, len(options.Vars))
+		for name, value := range options.Vars {
+			workspaceOptions.Vars[name] = value
+		}
+		workspace := filepath.Join(workspacesDir, smokeName, scenario)
+		// Module sources are relative, so the smoke test directory keeps its place next to the modules
+		workspaceOptions.TerraformDir = filepath.Join(workspace, "smoke_tests", smokeName)
+		workspaces = append(workspaces, &ScenarioWorkspace{Scenario: scenario, Dir: workspace, Options: &workspaceOptions})
+	}
+	return workspaces
+}
+
+// CopyScenario copies the modules, the shared smoke test files and the scenario file to the workspace.
+// Terraform state and providers already in the workspace are kept.
+func (w *ScenarioWorkspace) CopyScenario(smokeDir string, modulesDir string) error {
+	workspaceModules := filepath.Join(w.Dir, "modules")
+	if err := os.RemoveAll(workspaceModules); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(workspaceModules, 0o755); err != nil {
+		return err
+	}
+	if err := files.CopyFolderContentsWithFilter(modulesDir, workspaceModules, isTerraformSource); err != nil {
+		return err
+	}
+
+	previous, err := filepath.Glob(filepath.Join(w.Options.TerraformDir, "*.tf"))
+	if err != nil {
+		return err
+	}
+	for _, path := range previous {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(w.Options.TerraformDir, 0o755); err != nil {
+		return err
+	}
+	return files.CopyFolderContentsWithFilter(smokeDir, w.Options.TerraformDir, func(path string) bool {
+		name := filepath.Base(path)
+		return smokeSharedFiles[name] || name == w.Scenario+".tf"
+	})
+}
+
+// isTerraformSource skips the terraform working files of the copied modules
+func isTerraformSource(path string) bool {
+	name := filepath.Base(path)
+	return !strings.HasPrefix(name, ".") && !strings.HasSuffix(name, ".tfstate") && !strings.HasSuffix(name, ".tfstate.backup")
+}
+
+// SetupScenarios runs the setup stage of every workspace in parallel: each scenario is copied to its
+// workspace, then applied, or planned in plan-only mode, and its outputs are saved in the workspace.
+// A failing scenario fails its own subtest without stopping the others.
+// The outputs of all the workspaces are then read back, including those set up by a previous run when SKIP_setup is set.
+func SetupScenarios(t *testing.T, workspaces []*ScenarioWorkspace, smokeDir string, planOnly bool) (map[string][]byte, map[string]error) {
+	t.Run("setup", func(t *testing.T) {
+		for _, workspace := range workspaces {
+			t.Run(workspace.Scenario, func(t *testing.T) {
+				t.Parallel()
+				test_structure.RunTestStage(t, "setup", func() {
+					workspace.setup(t, smokeDir, planOnly)
+				})
+			})
+		}
+	})
+
+	raw := map[string][]byte{}
+	errs := map[string]error{}
+	for _, workspace := range workspaces {
+		path := workspace.outputsPath()
+		if !test_structure.IsTestDataPresent(t, path) {
+			t.Errorf("Scenario %s has no outputs: its setup failed or never ran", workspace.Scenario)
+			continue
+		}
+		var saved scenarioOutputs
+		test_structure.LoadTestData(t, path, &saved)
+		for key, output := range saved.Outputs {
+			raw[key] = output
+		}
+		for key, message := range saved.Errors {
+			errs[key] = errors.New(message)
+		}
+	}
+	return raw, errs
+}
+
+// TeardownScenarios runs the teardown stage of every workspace in parallel, destroying its resources and removing it.
+// A workspace that was never applied is only removed.
+// The workspace of a scenario whose destroy fails is kept, with the manifest of its leftover resources.
+func TeardownScenarios(t *testing.T, workspaces []*ScenarioWorkspace, planOnly bool) {
+	t.Run("teardown", func(t *testing.T) {
+		for _, workspace := range workspaces {
+			t.Run(workspace.Scenario, func(t *testing.T) {
+				t.Parallel()
+				test_structure.RunTestStage(t, "teardown", func() {
+					if !planOnly && workspace.Applied() {
+						require.NoError(t, workspace.Destroy(t), "Failed to destroy scenario %s", workspace.Scenario)
+					}
+					require.NoError(t, os.RemoveAll(workspace.Dir))
+				})
+			})
+		}
+	})
+}
+
+func (w *ScenarioWorkspace) setup(t *testing.T, smokeDir string, planOnly bool) {
+	// Outputs left by a previous run must not be read if this setup fails
+	if err := os.Remove(w.outputsPath()); err != nil && !os.IsNotExist(err) {
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.CopyScenario(smokeDir, filepath.Join(smokeDir, "..", "..", "modules")), "Failed to copy scenario %s", w.Scenario)
+
+	saved := scenarioOutputs{Outputs: map[string]json.RawMessage{}, Errors: map[string]string{}}
+	if planOnly {
+		raw, errs := plannedOutputs(InitAndPlanOnly(t, w.Options))
+		for key, output := range raw {
+			saved.Outputs[key] = output
+		}
+		for key, err := range errs {
+			saved.Errors[key] = err.Error()
+		}
+	} else {
+		w.applyStarted.Store(true)
+		terraform.InitAndApply(t, w.Options)
+		for key, output := range appliedOutputs(t, w.Options) {
+			saved.Outputs[key] = output
+		}
+	}
+	test_structure.SaveTestData(t, w.outputsPath(), true, saved)
+}
+
+func (w *ScenarioWorkspace) outputsPath() string {
+	return test_structure.FormatTestDataPath(w.Options.TerraformDir, "outputs.json")
+}
@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSelectScenarios tests that the scenarios are listed from the smoke test files and filtered by TERRAFORM_SCENARIOS
+func TestSelectScenarios(t *testing.T) {
+	scenarios, err := SmokeScenarios("../smoke_tests/ecs_ec2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent-only", "all-features-enabled", "bridge-networking", "host-networking", "socket-only", "tcp-enabled"}, scenarios)
+
+	t.Setenv(ScenariosEnvVar, "")
+	selected, err := SelectScenarios(scenarios)
+	require.NoError(t, err)
+	assert.Equal(t, scenarios, selected)
+
+	t.Setenv(ScenariosEnvVar, "socket-only, agent-only")
+	selected, err = SelectScenarios(scenarios)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"socket-only", "agent-only"}, selected)
+
+	t.Setenv(ScenariosEnvVar, "agent-only,cws-only")
+	_, err = SelectScenarios(scenarios)
+	assert.ErrorContains(t, err, "unknown scenarios cws-only")
+}
+
+// TestCopyScenario tests that a workspace only holds its scenario next to the modules, and drops the files of a previous copy
+func TestCopyScenario(t *testing.T) {
+	t.Setenv(ScenariosEnvVar, "cws-only")
+	workspaces := NewScenarioWorkspaces(t, &terraform.Options{
+		TerraformDir: "../smoke_tests/ecs_fargate",
+		Vars:         map[string]interface{}{"test_prefix": "terraform-test"},
+	})
+	require.Len(t, workspaces, 1)
+	workspace := workspaces[0]
+	assert.Equal(t, filepath.Join(".workspaces", "ecs_fargate", "cws-only", "smoke_tests", "ecs_fargate"), workspace.Options.TerraformDir)
+
+	workspace.Dir = t.TempDir()
+	workspace.Options.TerraformDir = filepath.Join(workspace.Dir, "smoke_tests", "ecs_fargate")
+	require.NoError(t, os.MkdirAll(workspace.Options.TerraformDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace.Options.TerraformDir, "all-null.tf"), nil, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace.Options.TerraformDir, "terraform.tfstate"), []byte("{}"), 0o644))
+
+	require.NoError(t, workspace.CopyScenario("../smoke_tests/ecs_fargate", "../modules"))
+
+	entries, err := os.ReadDir(workspace.Options.TerraformDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"cws-only.tf", "provider.tf", "terraform.tfstate", "variables.tf", "versions.tf"}, names)
+	assert.FileExists(t, filepath.Join(workspace.Dir, "modules", "ecs_fargate", "main.tf"))
+}